@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/urfave/cli/v3"
+	"sigs.k8s.io/yaml"
+)
+
+func cmdConfig() *cli.Command {
+	return &cli.Command{
+		Name:  "config",
+		Usage: "Manage the config file",
+		Commands: []*cli.Command{
+			{
+				Name: "view",
+				Usage: `Validate and show the config file. If --profile or --context are set, show the
+trace flag values set by the config for them instead`,
+				Flags: []cli.Flag{
+					&cli.StringFlag{Name: "context", Aliases: []string{"ctx"}, Usage: "Kubernetes context to resolve the flags for"},
+				},
+				Action: runConfigView,
+			},
+		},
+	}
+}
+
+func runConfigView(_ context.Context, c *cli.Command) error {
+	cfg, err := loadConfig(c, cmdTrace().Flags)
+	if err != nil {
+		return err
+	}
+
+	var out any = cfg
+	if c.IsSet("profile") || c.IsSet("context") {
+		if out, err = resolveConfig(c, cfg); err != nil {
+			return err
+		}
+	}
+
+	b, err := yaml.Marshal(out)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "# %s\n%s", c.String("config"), b)
+	return nil
+}
@@ -2,31 +2,49 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"maps"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/brunoluiz/xpdig/internal/bubbles/action/kubeapi"
 	"github.com/brunoluiz/xpdig/internal/bubbles/action/kubectl"
 	"github.com/brunoluiz/xpdig/internal/bubbles/action/shell"
 	"github.com/brunoluiz/xpdig/internal/bubbles/app"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/keyhelp"
 	"github.com/brunoluiz/xpdig/internal/bubbles/component/navigator"
 	"github.com/brunoluiz/xpdig/internal/bubbles/component/statusbar"
 	"github.com/brunoluiz/xpdig/internal/bubbles/component/table"
 	"github.com/brunoluiz/xpdig/internal/bubbles/layout/xpnavigator"
+	"github.com/brunoluiz/xpdig/internal/bubbles/theme"
+	"github.com/brunoluiz/xpdig/internal/config"
 	"github.com/brunoluiz/xpdig/internal/xplane"
+	"github.com/brunoluiz/xpdig/internal/xplane/column"
+	"github.com/brunoluiz/xpdig/internal/xplane/export"
+	"github.com/brunoluiz/xpdig/internal/xplane/notifier"
+	"github.com/brunoluiz/xpdig/internal/xplane/otlp"
+	"github.com/brunoluiz/xpdig/internal/xplane/query"
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
-	"github.com/charmbracelet/x/ansi"
 	"github.com/urfave/cli/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
 )
 
 // nolint: funlen
 func cmdTrace() *cli.Command {
+	// The config is loaded once, before flags are parsed, as it sets their defaults
+	var cfg config.Config
+
 	return &cli.Command{
 		Usage: `Explore tracing from Crossplane. Usage is available through arguments or data stream
 1. To load it straight from a live resource using the crossplane CLI, do 'xpdig trace <object name>'
+   Passing more than one object, eg: 'xpdig trace A/a B/b', opens each of them in a tab
 2. To load it from a trace JSON file, do 'crossplane beta trace -o json <> | xpdig trace --stdin'
 
 Live mode is only available for (1) through the use of --watch / --watch-interval (see flag usage below)`,
@@ -41,7 +59,32 @@ Live mode is only available for (1) through the use of --watch / --watch-interva
 			&cli.StringFlag{Name: "context", Aliases: []string{"ctx"}, Usage: "Kubernetes context to be used"},
 			&cli.StringFlag{Name: "namespace", Aliases: []string{"n", "ns"}, Usage: "Kubernetes namespace to be used"},
 			&cli.BoolFlag{Name: "stdin", Aliases: []string{"in"}, Usage: "Specify in case file is piped into stdin"},
-			&cli.BoolFlag{Name: "short", Usage: "Return short result columns for small screens"},
+			&cli.BoolFlag{Name: "short", Usage: "Return short result columns for small screens (default: based on the terminal width)"},
+			&cli.StringFlag{
+				Name:  "filter",
+				Usage: "Only show resources matching a query, eg: 'kind:Bucket AND synced!=True' (see README for the syntax)",
+			},
+			&cli.StringSliceFlag{
+				Name:  "columns",
+				Usage: "Extra columns as TITLE[@Kind.group|...]=jsonpath pairs, eg: 'REGION@Bucket=.spec.forProvider.region'",
+			},
+			&cli.BoolFlag{
+				Name:  "native",
+				Usage: "Run get, describe, edit, delete and annotate through the Kubernetes API, instead of shelling out to kubectl",
+			},
+			&cli.StringFlag{
+				Name:  "delete-propagation",
+				Usage: "How dependents are deleted with --native (available: background, foreground, orphan)",
+				Value: string(metav1.DeletePropagationBackground),
+			},
+			&cli.BoolFlag{Name: "read-only", Usage: "Disable actions changing resources (edit, delete, annotate, pause and reconcile)"},
+			&cli.StringFlag{
+				Name:  "theme",
+				Value: theme.Auto,
+				Usage: "Colour theme: auto, dark, light, high-contrast, mono or a theme from the config file (auto is mono when NO_COLOR is set)",
+			},
+			&cli.BoolFlag{Name: "mouse", Usage: "Enable mouse support (click to focus or sort, wheel to scroll), which disables terminal text selection"},
+			&cli.BoolFlag{Name: "only-unhealthy", Usage: "Show only unhealthy resources (and their ancestors)"},
 			&cli.BoolFlag{Name: "watch", Aliases: []string{"w"}, Usage: "Refresh trace every 10 seconds"},
 			&cli.DurationFlag{
 				Name:    "watch-interval",
@@ -49,13 +92,74 @@ Live mode is only available for (1) through the use of --watch / --watch-interva
 				Usage:   "Refresh interval for the watcher feature",
 				Value:   5 * time.Second,
 			},
+			&cli.StringFlag{Name: "dot", Usage: "Export the trace as a Graphviz DOT file and exit (use '-' for stdout)"},
+			&cli.StringFlag{Name: "mermaid", Usage: "Export the trace as a Mermaid flowchart file and exit (use '-' for stdout)"},
+			&cli.StringFlag{Name: "html", Usage: "Export the trace as a self-contained HTML report and exit (use '-' for stdout)"},
+			&cli.StringFlag{
+				Name:  "otlp-endpoint",
+				Usage: "Export provisioning as OpenTelemetry spans to an OTLP/HTTP endpoint and exit (eg: http://localhost:4318/v1/traces)",
+			},
+			&cli.StringFlag{Name: "otlp-file", Usage: "Export provisioning as OpenTelemetry spans into a JSON file and exit, for offline usage"},
+			&cli.StringFlag{Name: "notify-webhook", Usage: "While watching, POST a JSON payload to this URL whenever a resource transitions"},
+			&cli.StringSliceFlag{Name: "notify-kind", Usage: "Only notify about transitions of these kinds (eg: Bucket)"},
+			&cli.StringFlag{
+				Name:  "notify-severity",
+				Usage: "Minimum severity to notify about (available: info, error)",
+				Value: string(notifier.SeverityInfo),
+			},
+			&cli.IntFlag{Name: "notify-rate-limit", Usage: "Maximum notifications per minute (0 disables it)", Value: 30},
+			&cli.BoolFlag{Name: "notify-bell", Usage: "While watching, ring the terminal bell whenever a resource transitions"},
+			&cli.BoolFlag{Name: "notify-desktop", Usage: "While watching, send an OSC 9 desktop notification whenever a resource transitions"},
+		},
+		Before: func(ctx context.Context, c *cli.Command) (context.Context, error) {
+			var err error
+			cfg, err = applyConfig(c)
+			return ctx, err
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			return runTrace(ctx, c, cfg)
 		},
-		Action: runTrace,
 	}
 }
 
-func runTrace(ctx context.Context, c *cli.Command) error {
-	tracer, err := getTracer(c, logger.With("component", "tracer"))
+func runTrace(ctx context.Context, c *cli.Command, cfg config.Config) error {
+	tracers, err := getTracers(c, logger.With("component", "tracer"))
+	if err != nil {
+		return err
+	}
+
+	if exports := getExports(c); len(exports) > 0 || isOTLPExport(c) {
+		if len(tracers) > 1 {
+			return ErrExportMultiple
+		}
+		return runExport(ctx, c, tracers[0].tracer, exports)
+	}
+
+	if _, err := query.Parse(c.String("filter")); err != nil {
+		return fmt.Errorf("invalid filter: %w", err)
+	}
+
+	propagation, err := kubeapi.ParsePropagation(c.String("delete-propagation"))
+	if err != nil {
+		return err
+	}
+
+	columns, err := getColumns(c, cfg)
+	if err != nil {
+		return err
+	}
+
+	keyMaps, err := getKeyMaps(cfg)
+	if err != nil {
+		return err
+	}
+
+	th, err := theme.Get(c.String("theme"), cfg.Themes)
+	if err != nil {
+		return err
+	}
+
+	newNotifier, err := getNotifier(c, logger.With("component", "notifier"))
 	if err != nil {
 		return err
 	}
@@ -69,72 +173,328 @@ func runTrace(ctx context.Context, c *cli.Command) error {
 			"flags":   getFlags(c),
 		})
 
+	newPane := newPaneFunc(c, cfg, columns, th, keyMaps, newNotifier)
+	retarget := newTracer(c, logger.With("component", "tracer"))
+	panes := make([]xpnavigator.Model, 0, len(tracers))
+	for _, t := range tracers {
+		panes = append(panes, newPane(t.tracer, t.opt))
+	}
+
 	program := tea.NewProgram(
-		app.New(
-			logger.With("component", "bubbles/app"),
-			kubectl.New(c.String("context"), shell.New(logger.With("component", "bubbles/action/shell"))),
-			xpnavigator.New(
-				logger.With("component", "bubbles/layout/xpnavigator"),
-				navigator.New(
-					logger.With("component", "bubbles/component/navigator"),
-					table.New(
-						table.WithFocused(true),
-						table.WithStyles(func() table.Styles {
-							s := table.DefaultStyles()
-							s.Selected = lipgloss.NewStyle().
-								Foreground(lipgloss.ANSIColor(ansi.Black)).
-								Background(lipgloss.ANSIColor(ansi.White))
-							return s
-						}()),
-					),
-					textinput.New(),
-				),
-				statusbar.New(),
-				tracer,
-				xpnavigator.WithWatch(c.Bool("watch")),
-				xpnavigator.WithWatchInterval(c.Duration("watch-interval")),
-				xpnavigator.WithShortColumns(c.Bool("short")),
-			),
-		),
-		tea.WithAltScreen(),
-		tea.WithContext(ctx),
+		newApp(c, cfg, th, keyMaps, propagation, panes, func(t xpnavigator.Target) xpnavigator.Model {
+			return newPane(retarget(t), xpnavigator.WithTarget(t, retarget))
+		}),
+		programOptions(ctx, c)...,
 	)
 
 	_, err = program.Run()
 	return err
 }
 
+// newPaneFunc returns how panes (one per tab) are created, all of them sharing
+// the same flags, config, theme and keybindings.
+func newPaneFunc(
+	c *cli.Command,
+	cfg config.Config,
+	columns []*column.Column,
+	th theme.Theme,
+	keyMaps keyMaps,
+	newNotifier func() xpnavigator.WithOpt,
+) func(xpnavigator.Tracer, ...xpnavigator.WithOpt) xpnavigator.Model {
+	return func(tracer xpnavigator.Tracer, opts ...xpnavigator.WithOpt) xpnavigator.Model {
+		return xpnavigator.New(
+			logger.With("component", "bubbles/layout/xpnavigator"),
+			navigator.New(
+				logger.With("component", "bubbles/component/navigator"),
+				table.New(
+					table.WithFocused(true),
+					table.WithKeyMap(keyMaps.table),
+					table.WithStyles(tableStyles(th)),
+				),
+				textinput.New(),
+				navigator.WithKeyMap(keyMaps.navigator),
+				navigator.WithStyles(navigatorStyles(th)),
+			),
+			statusbar.New(
+				statusbar.WithPrimaryStatusColor(th.Status.StatusBar()),
+				statusbar.WithSecondaryStatusColor(th.Info.StatusBar()),
+				statusbar.WithNeutralStatusColor(th.Neutral.StatusBar()),
+				statusbar.WithErrorStatusColor(th.Alert.StatusBar()),
+			),
+			tracer,
+			append([]xpnavigator.WithOpt{
+				xpnavigator.WithWatch(c.Bool("watch")),
+				xpnavigator.WithExternalWatch(),
+				getLayoutOpt(c),
+				xpnavigator.WithLayoutStore(config.LayoutFile(config.DefaultLayoutPath())),
+				xpnavigator.WithColumns(columns),
+				xpnavigator.WithFilter(c.String("filter")),
+				xpnavigator.WithOnlyUnhealthy(c.Bool("only-unhealthy")),
+				xpnavigator.WithContexts(getKubeContexts()),
+				xpnavigator.WithReadOnly(c.Bool("read-only"), readOnlyContexts(cfg)),
+				newNotifier(),
+				xpnavigator.WithKeyMap(keyMaps.trace),
+				xpnavigator.WithModalKeyMaps(keyMaps.columns, keyMaps.popup, keyMaps.confirm, keyMaps.palette, keyMaps.help, keyMaps.refs),
+				xpnavigator.WithStyles(xpnavigator.Styles{
+					Spinner:   th.Accent.Lipgloss(),
+					Unhealthy: th.Unhealthy.Lipgloss(),
+					Paused:    th.Paused.Lipgloss(),
+				}),
+				xpnavigator.WithGlyphs(xpnavigator.Glyphs(th.Glyphs)),
+				xpnavigator.WithModalStyles(modalStyles(th)),
+				xpnavigator.WithHelpGroups(keyhelp.Group{Title: "Tabs", Bindings: []key.Binding{
+					keyMaps.app.NextTab, keyMaps.app.PrevTab, keyMaps.app.GoToTab, keyMaps.app.CloseTab,
+				}}),
+			}, opts...)...,
+		)
+	}
+}
+
+// newApp creates the app with its first panes, opening more on demand.
+func newApp(
+	c *cli.Command,
+	cfg config.Config,
+	th theme.Theme,
+	keyMaps keyMaps,
+	propagation metav1.DeletionPropagation,
+	panes []xpnavigator.Model,
+	newPane func(xpnavigator.Target) xpnavigator.Model,
+) *app.Model {
+	return app.New(
+		logger.With("component", "bubbles/app"),
+		getActions(c, shell.New(
+			logger.With("component", "bubbles/action/shell"),
+			shell.WithPager(cfg.Pager),
+			shell.WithEditor(cfg.Editor),
+		), propagation),
+		panes[0],
+		app.WithTabs(panes[1:]...),
+		app.WithNewTab(newPane),
+		app.WithWatch(c.Bool("watch"), c.Duration("watch-interval")),
+		app.WithKeyMap(keyMaps.app),
+		app.WithStyles(appStyles(th)),
+		app.WithGlyphs(xpnavigator.Glyphs(th.Glyphs)),
+	)
+}
+
+// programOptions enables the mouse only if asked, as it takes over the
+// terminal text selection.
+func programOptions(ctx context.Context, c *cli.Command) []tea.ProgramOption {
+	opts := []tea.ProgramOption{tea.WithAltScreen(), tea.WithContext(ctx)}
+	if c.Bool("mouse") {
+		opts = append(opts, tea.WithMouseCellMotion())
+	}
+	return opts
+}
+
+// getColumns returns the custom columns from the config file, followed by
+// the ones passed as flags.
+func getColumns(c *cli.Command, cfg config.Config) ([]*column.Column, error) {
+	columns, err := cfg.GetColumns()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, spec := range c.StringSlice("columns") {
+		col, err := column.Parse(spec)
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, col)
+	}
+	return columns, nil
+}
+
+// getLayoutOpt pins the layout to short or wide, if set, as otherwise it is
+// chosen at runtime.
+func getLayoutOpt(c *cli.Command) xpnavigator.WithOpt {
+	if !c.IsSet("short") {
+		return func(*xpnavigator.Model) {}
+	}
+	return xpnavigator.WithShortColumns(c.Bool("short"))
+}
+
+func getExports(c *cli.Command) map[export.Format]string {
+	exports := map[export.Format]string{}
+	for _, f := range []export.Format{export.FormatDOT, export.FormatMermaid, export.FormatHTML} {
+		if dst := c.String(string(f)); dst != "" {
+			exports[f] = dst
+		}
+	}
+	return exports
+}
+
+func isOTLPExport(c *cli.Command) bool {
+	return c.String("otlp-endpoint") != "" || c.String("otlp-file") != ""
+}
+
+func runExport(ctx context.Context, c *cli.Command, tracer xpnavigator.Tracer, exports map[export.Format]string) error {
+	trace, err := tracer.GetTrace()
+	if err != nil {
+		return err
+	}
+
+	meta := export.Meta{CapturedAt: time.Now(), Context: getKubeContext(c)}
+	for f, dst := range exports {
+		if err := export.WriteFile(dst, f, trace, meta); err != nil {
+			return err
+		}
+	}
+
+	if !isOTLPExport(c) {
+		return nil
+	}
+
+	exp, err := otlp.NewExporter(ctx, c.String("otlp-endpoint"), c.String("otlp-file"))
+	if err != nil {
+		return err
+	}
+	return otlp.Export(ctx, exp, trace, meta.CapturedAt)
+}
+
+// getKubeContext returns the context the trace was taken from. Piped traces
+// could come from anywhere, so only the flag is trusted for those.
+func getKubeContext(c *cli.Command) string {
+	if ctx := c.String("context"); ctx != "" || c.Bool("stdin") {
+		return ctx
+	}
+
+	return currentKubeContext()
+}
+
+// getNotifier returns how to set up the notifier of a tab. Notifiers keep the
+// last trace, so every tab gets its own, all sharing the same sinks.
+func getNotifier(c *cli.Command, logger *slog.Logger) (func() xpnavigator.WithOpt, error) {
+	sinks := []notifier.Sink{}
+	if url := c.String("notify-webhook"); url != "" {
+		sinks = append(sinks, notifier.NewWebhook(url))
+	}
+	if c.Bool("notify-bell") || c.Bool("notify-desktop") {
+		sinks = append(sinks, notifier.NewTerminal(os.Stderr, c.Bool("notify-bell"), c.Bool("notify-desktop")))
+	}
+	if len(sinks) == 0 {
+		return func() xpnavigator.WithOpt { return func(*xpnavigator.Model) {} }, nil
+	}
+
+	severity, err := notifier.ParseSeverity(c.String("notify-severity"))
+	if err != nil {
+		return nil, err
+	}
+
+	opts := []notifier.WithOpt{
+		notifier.WithKinds(c.StringSlice("notify-kind")),
+		notifier.WithMinSeverity(severity),
+		notifier.WithRateLimit(c.Int("notify-rate-limit")),
+	}
+	return func() xpnavigator.WithOpt {
+		return xpnavigator.WithNotifier(notifier.New(logger, sinks, opts...))
+	}, nil
+}
+
 type ErrInvalidArgument struct{}
 
 func (e *ErrInvalidArgument) Error() string {
-	return "trace for is not possible: argument must be on the format '<kind>/<name>' or '<kind> <name>'"
+	return "trace for is not possible: arguments must be on the format '<kind>/<name> [<kind>/<name> ...]' or '<kind> <name>'"
+}
+
+// ErrExportMultiple is returned when exporting while tracing multiple objects.
+var ErrExportMultiple = errors.New("exports are only possible when tracing a single object")
+
+// paneTracer is how the trace of a tab is loaded, with opt allowing to change
+// what is traced at runtime, which is only possible when tracing live resources.
+type paneTracer struct {
+	tracer xpnavigator.Tracer
+	opt    xpnavigator.WithOpt
+}
+
+// actions are run by the app against the objects of the trace.
+type actions interface {
+	Edit(ns string, resources ...string) tea.Cmd
+	Describe(ns, resource string) tea.Cmd
+	Get(ns, resource string) tea.Cmd
+	Delete(ns string, resources ...string) tea.Cmd
+	Annotate(ns string, annotations []string, resources ...string) tea.Cmd
+	SetContext(kubectx string)
 }
 
-func getTracer(c *cli.Command, logger *slog.Logger) (xpnavigator.Tracer, error) {
+// getActions returns the actions going through the Kubernetes API if --native
+// is set, otherwise the ones shelling out to kubectl.
+func getActions(c *cli.Command, sh *shell.Cmd, propagation metav1.DeletionPropagation) actions {
+	if c.Bool("native") {
+		return kubeapi.New(c.String("context"), sh, kubeapi.WithPropagation(propagation))
+	}
+	return kubectl.New(c.String("context"), sh)
+}
+
+// getTracers returns a tracer per object passed as argument, or a single one
+// reading from stdin.
+func getTracers(c *cli.Command, logger *slog.Logger) ([]paneTracer, error) {
 	if c.Bool("stdin") {
-		return xplane.NewReaderTraceQuerier(os.Stdin), nil
+		return []paneTracer{{
+			tracer: xplane.NewReaderTraceQuerier(os.Stdin),
+			opt:    func(*xpnavigator.Model) {},
+		}}, nil
 	}
 
-	var kind, object string
-	switch c.Args().Len() {
-	case 1:
-		n1 := c.Args().First()
-		res := strings.Split(n1, "/")
-		if len(res) != 2 {
-			return nil, &ErrInvalidArgument{}
+	targets, err := getTargets(c)
+	if err != nil {
+		return nil, err
+	}
+
+	tracers := make([]paneTracer, 0, len(targets))
+	for _, t := range targets {
+		tracers = append(tracers, paneTracer{
+			tracer: newTracer(c, logger)(t),
+			opt:    xpnavigator.WithTarget(t, newTracer(c, logger)),
+		})
+	}
+	return tracers, nil
+}
+
+// newTracer returns a function creating tracers for any target, so it can be
+// changed at runtime (eg: `:ns`).
+func newTracer(c *cli.Command, logger *slog.Logger) func(xpnavigator.Target) xpnavigator.Tracer {
+	return func(t xpnavigator.Target) xpnavigator.Tracer {
+		return xplane.NewCLITraceQuerier(logger, c.String("cmd"), t.Namespace, t.Context, t.Kind, t.Object)
+	}
+}
+
+// getTargets returns the objects passed as arguments, either as `<kind> <name>`
+// or as one or more `<kind>/<name>`.
+func getTargets(c *cli.Command) ([]xpnavigator.Target, error) {
+	args := c.Args().Slice()
+	target := func(kind, object string) xpnavigator.Target {
+		return xpnavigator.Target{
+			Namespace: c.String("namespace"),
+			Context:   getKubeContext(c),
+			Kind:      kind,
+			Object:    object,
 		}
-		kind, object = res[0], res[1]
-	case 2:
-		kind, object = c.Args().Get(0), c.Args().Get(1)
-	default:
+	}
+
+	if len(args) == 2 && !strings.Contains(args[0], "/") && !strings.Contains(args[1], "/") {
+		return []xpnavigator.Target{target(args[0], args[1])}, nil
+	}
+	if len(args) == 0 {
 		return nil, &ErrInvalidArgument{}
 	}
 
-	return xplane.NewCLITraceQuerier(
-		logger,
-		c.String("cmd"),
-		c.String("namespace"),
-		c.String("context"),
-		kind, object,
-	), nil
+	targets := make([]xpnavigator.Target, 0, len(args))
+	for _, arg := range args {
+		kind, object, ok := strings.Cut(arg, "/")
+		if !ok || kind == "" || object == "" || strings.Contains(object, "/") {
+			return nil, &ErrInvalidArgument{}
+		}
+		targets = append(targets, target(kind, object))
+	}
+	return targets, nil
+}
+
+// getKubeContexts returns the contexts available in the kubeconfig.
+func getKubeContexts() []string {
+	cfg, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
+	if err != nil {
+		return nil
+	}
+	return slices.Sorted(maps.Keys(cfg.Contexts))
 }
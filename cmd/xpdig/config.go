@@ -0,0 +1,162 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/brunoluiz/xpdig/internal/config"
+	"github.com/urfave/cli/v3"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// flagReadOnly can not be disabled by flag once enabled for a context.
+const flagReadOnly = "read-only"
+
+// loadConfig loads and validates the config file against the flags of cmd.
+func loadConfig(c *cli.Command, flags []cli.Flag) (config.Config, error) {
+	cfg, err := config.Load(c.String("config"))
+	if err != nil {
+		return cfg, err
+	}
+
+	err = cfg.Validate(func(name string, value any) error {
+		_, err := flagValue(flags, name, value)
+		return err
+	})
+	return cfg, err
+}
+
+// applyConfig sets flags which were not set by the user, using the defaults,
+// the selected profile and the overrides of the Kubernetes context used. It
+// returns the config, so it is not loaded again.
+func applyConfig(c *cli.Command) (config.Config, error) {
+	cfg, err := loadConfig(c, c.Flags)
+	if err != nil {
+		return cfg, err
+	}
+
+	flags, err := resolveConfig(c, cfg)
+	if err != nil {
+		return cfg, err
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(flags)) {
+		if c.IsSet(name) && (name != flagReadOnly || flags[name] != true) {
+			continue
+		}
+
+		value, err := flagValue(c.Flags, name, flags[name])
+		if err != nil {
+			return cfg, err
+		}
+		if err := c.Set(name, value); err != nil {
+			return cfg, &config.ErrInvalid{Field: name, Msg: err.Error()}
+		}
+	}
+	return cfg, nil
+}
+
+// resolveConfig returns the flags set by the config for the selected profile,
+// followed by the ones of the Kubernetes context used.
+func resolveConfig(c *cli.Command, cfg config.Config) (config.Flags, error) {
+	flags, err := cfg.Resolve(c.String("profile"))
+	if err != nil {
+		return nil, err
+	}
+
+	kubectx := c.String("context")
+	if !c.IsSet("context") {
+		if ctx, ok := flags["context"].(string); ok {
+			kubectx = ctx
+		}
+	}
+	if kubectx == "" {
+		kubectx = currentKubeContext()
+	}
+	maps.Copy(flags, cfg.Contexts[kubectx])
+	return flags, nil
+}
+
+// flagValue validates a config value against the flag it is meant for,
+// returning it in the format used in the command line.
+func flagValue(flags []cli.Flag, name string, value any) (string, error) {
+	var flag cli.Flag
+	for _, f := range flags {
+		if slices.Contains(f.Names(), name) {
+			flag = f
+			break
+		}
+	}
+	if flag == nil {
+		return "", fmt.Errorf("unknown flag '%s'", name)
+	}
+
+	switch flag.(type) {
+	case *cli.BoolFlag:
+		if v, ok := value.(bool); ok {
+			return strconv.FormatBool(v), nil
+		}
+		return "", errors.New("expected a boolean")
+	case *cli.IntFlag:
+		if v, ok := value.(float64); ok && v == float64(int64(v)) {
+			return strconv.FormatInt(int64(v), 10), nil
+		}
+		return "", errors.New("expected an integer")
+	case *cli.DurationFlag:
+		v, ok := value.(string)
+		if !ok {
+			return "", errors.New("expected a duration (eg: 10s)")
+		}
+		if _, err := time.ParseDuration(v); err != nil {
+			return "", err
+		}
+		return v, nil
+	case *cli.StringSliceFlag:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+		items, ok := value.([]any)
+		if !ok {
+			return "", errors.New("expected a list of strings")
+		}
+		values := []string{}
+		for _, item := range items {
+			v, ok := item.(string)
+			if !ok {
+				return "", errors.New("expected a list of strings")
+			}
+			values = append(values, v)
+		}
+		return strings.Join(values, ","), nil
+	default:
+		if v, ok := value.(string); ok {
+			return v, nil
+		}
+		return "", errors.New("expected a string")
+	}
+}
+
+// readOnlyContexts returns which contexts are configured as read-only.
+func readOnlyContexts(cfg config.Config) []string {
+	contexts := []string{}
+	for name, flags := range cfg.Contexts {
+		if flags[flagReadOnly] == true {
+			contexts = append(contexts, name)
+		}
+	}
+	slices.Sort(contexts)
+	return contexts
+}
+
+func currentKubeContext() string {
+	cfg, err := clientcmd.NewDefaultClientConfigLoadingRules().Load()
+	if err != nil {
+		return ""
+	}
+	return cfg.CurrentContext
+}
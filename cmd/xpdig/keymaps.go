@@ -0,0 +1,131 @@
+package main
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/app"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/columnmanager"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/confirm"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/keyhelp"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/navigator"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/palette"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/picker"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/popup"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/table"
+	"github.com/brunoluiz/xpdig/internal/bubbles/keys"
+	"github.com/brunoluiz/xpdig/internal/bubbles/layout/xpnavigator"
+	"github.com/brunoluiz/xpdig/internal/config"
+)
+
+// keyMaps are the keybindings of every component.
+type keyMaps struct {
+	app       app.KeyMap
+	trace     xpnavigator.KeyMap
+	navigator navigator.KeyMap
+	table     table.KeyMap
+	columns   columnmanager.KeyMap
+	popup     popup.KeyMap
+	confirm   confirm.KeyMap
+	palette   palette.KeyMap
+	help      keyhelp.KeyMap
+	refs      picker.KeyMap
+}
+
+// getKeyMaps returns the default keybindings, remapped by the config file,
+// failing if any key ends up bound to more than one action at the same time.
+func getKeyMaps(cfg config.Config) (keyMaps, error) {
+	km := keyMaps{
+		app:       app.DefaultKeyMap(),
+		trace:     xpnavigator.DefaultKeyMap(),
+		navigator: navigator.DefaultKeyMap(),
+		table:     table.DefaultKeyMap(),
+		columns:   columnmanager.DefaultKeyMap(),
+		popup:     popup.DefaultKeyMap(),
+		confirm:   confirm.DefaultKeyMap(),
+		palette:   palette.DefaultKeyMap(),
+		help:      keyhelp.DefaultKeyMap(),
+		refs:      picker.DefaultKeyMap(),
+	}
+	scopes := map[string]any{
+		"app":        &km.app,
+		"trace":      &km.trace,
+		"navigator":  &km.navigator,
+		"table":      &km.table,
+		"columns":    &km.columns,
+		"popup":      &km.popup,
+		"confirm":    &km.confirm,
+		"palette":    &km.palette,
+		"help":       &km.help,
+		"references": &km.refs,
+	}
+
+	for _, scope := range slices.Sorted(maps.Keys(cfg.Keys)) {
+		keymap, ok := scopes[scope]
+		if !ok {
+			return km, &config.ErrInvalid{Field: "keys." + scope, Msg: "unknown component"}
+		}
+		for _, action := range []string{"lineUp", "lineDown"} {
+			if _, ok := cfg.Keys[scope][action]; ok && scope == "table" {
+				return km, &config.ErrInvalid{Field: "keys.table." + action, Msg: "remap navigator.up/navigator.down instead"}
+			}
+		}
+		if err := keys.Remap(scope, keymap, cfg.Keys[scope]); err != nil {
+			return km, &config.ErrInvalid{Field: "keys", Msg: err.Error()}
+		}
+	}
+
+	if err := km.conflicts(); err != nil {
+		return km, &config.ErrInvalid{Field: "keys", Msg: err.Error()}
+	}
+	return km, nil
+}
+
+// conflicts checks the bindings active at the same time: while browsing the
+// tree, while typing a search and within each modal (or its search).
+func (km keyMaps) conflicts() error {
+	quit := slices.DeleteFunc(keys.Actions("app", km.app), func(a keys.Action) bool { return a.Name != "app.quit" })
+	nav := keys.Actions("navigator", km.navigator)
+	searching := []string{"navigator.searchConfirm", "navigator.searchMode"}
+	isSearching := func(a keys.Action) bool { return slices.Contains(searching, a.Name) }
+	isHelpSearching := func(a keys.Action) bool {
+		return a.Name == "help.searchConfirm" || a.Name == "help.searchQuit"
+	}
+
+	// Table keys also bound by the navigator are dropped (see navigator.New)
+	table := slices.DeleteFunc(keys.Actions("table", km.table), func(a keys.Action) bool {
+		return a.Name == "table.lineUp" || a.Name == "table.lineDown"
+	})
+	for i := range table {
+		table[i].Binding = keys.Without(table[i].Binding, nav)
+	}
+
+	browsing := slices.Concat(
+		slices.DeleteFunc(keys.Actions("app", km.app), func(a keys.Action) bool { return a.Name == "app.failQuit" }),
+		keys.Actions("trace", km.trace),
+		slices.DeleteFunc(slices.Clone(nav), isSearching),
+		table,
+	)
+	typing := slices.Concat(quit, slices.DeleteFunc(slices.Clone(nav), func(a keys.Action) bool {
+		return !isSearching(a) && a.Name != "navigator.searchQuit"
+	}))
+
+	for _, actions := range [][]keys.Action{
+		browsing,
+		typing,
+		slices.Concat(quit, keys.Actions("columns", km.columns)),
+		slices.Concat(quit, keys.Actions("popup", km.popup)),
+		slices.Concat(quit, keys.Actions("confirm", km.confirm)),
+		slices.Concat(quit, keys.Actions("palette", km.palette)),
+		slices.Concat(quit, keys.Actions("references", km.refs)),
+		slices.Concat(quit, slices.DeleteFunc(keys.Actions("help", km.help), isHelpSearching)),
+		slices.Concat(quit, slices.DeleteFunc(keys.Actions("help", km.help), func(a keys.Action) bool {
+			return !isHelpSearching(a)
+		})),
+	} {
+		if err := keys.Conflicts(actions); err != nil {
+			return err
+		}
+	}
+	return nil
+}
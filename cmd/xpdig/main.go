@@ -9,6 +9,7 @@ import (
 	"os/signal"
 	"syscall"
 
+	"github.com/brunoluiz/xpdig/internal/config"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/urfave/cli/v3"
 )
@@ -20,6 +21,16 @@ func cmdMain(cmds ...*cli.Command) *cli.Command {
 		Name:  "xpdig",
 		Usage: "Set of tools to explore your crossplane resources",
 		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "config",
+				Usage: "Config file path",
+				Value: config.DefaultPath(),
+			},
+			&cli.StringFlag{
+				Name:    "profile",
+				Aliases: []string{"p"},
+				Usage:   "Config profile to use, setting flag defaults (see README)",
+			},
 			&cli.StringFlag{
 				Name:    "log",
 				Aliases: []string{"l"},
@@ -77,6 +88,7 @@ func main() {
 
 	if err := cmdMain(
 		cmdTrace(),
+		cmdConfig(),
 		cmdVersion(),
 	).Run(ctx, os.Args); err != nil {
 		if !errors.Is(err, tea.ErrInterrupted) {
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"github.com/brunoluiz/xpdig/internal/bubbles/app"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/columnmanager"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/confirm"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/keyhelp"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/navigator"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/palette"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/picker"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/popup"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/table"
+	"github.com/brunoluiz/xpdig/internal/bubbles/theme"
+)
+
+func appStyles(th theme.Theme) app.Styles {
+	return app.Styles{
+		Tab:       th.Neutral.Lipgloss().Padding(0, 1),
+		ActiveTab: th.Status.Lipgloss().Bold(true).Padding(0, 1),
+		Unhealthy: th.Unhealthy.Lipgloss(),
+	}
+}
+
+func tableStyles(th theme.Theme) table.Styles {
+	s := table.DefaultStyles()
+	s.Selected = th.Selected.Lipgloss()
+	return s
+}
+
+func navigatorStyles(th theme.Theme) navigator.Styles {
+	s := navigator.DefaultStyles()
+	s.Help = th.Text.Lipgloss()
+	s.Error = th.Error.Lipgloss()
+	s.Match = th.Match.Lipgloss()
+	return s
+}
+
+// modalStyles returns the styles of the column manager, popup, confirmation,
+// command palette, key bindings and references.
+func modalStyles(th theme.Theme) (
+	columnmanager.Styles, popup.Styles, confirm.Styles, palette.Styles, keyhelp.Styles, picker.Styles,
+) {
+	columns := columnmanager.DefaultStyles()
+	columns.Selected = th.Selected.Lipgloss()
+	columns.Hidden = th.Muted.Lipgloss()
+	columns.Help = th.Text.Lipgloss()
+
+	popupStyles := popup.DefaultStyles()
+	popupStyles.Help = th.Text.Lipgloss()
+
+	confirmStyles := confirm.DefaultStyles()
+	confirmStyles.Help = th.Text.Lipgloss()
+
+	paletteStyles := palette.DefaultStyles()
+	paletteStyles.Hint = th.Muted.Lipgloss()
+	paletteStyles.Error = th.Error.Lipgloss()
+
+	keyHelpStyles := keyhelp.DefaultStyles()
+	keyHelpStyles.Desc = th.Text.Lipgloss()
+	keyHelpStyles.Help = th.Text.Lipgloss()
+
+	pickerStyles := picker.DefaultStyles()
+	pickerStyles.Selected = th.Selected.Lipgloss()
+	pickerStyles.Description = th.Muted.Lipgloss()
+	pickerStyles.Help = th.Text.Lipgloss()
+
+	return columns, popupStyles, confirmStyles, paletteStyles, keyHelpStyles, pickerStyles
+}
@@ -0,0 +1,216 @@
+package kubeapi
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/action/shell"
+	tea "github.com/charmbracelet/bubbletea"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/yaml"
+)
+
+// ErrConflict is returned when an object was changed by someone else while
+// being edited, so the edit is not applied over it.
+type ErrConflict struct {
+	Resource string
+}
+
+func (e *ErrConflict) Error() string {
+	return fmt.Sprintf("%s was changed while being edited: edit it again", e.Resource)
+}
+
+// ErrEditUnchanged is returned when an edit is closed without changes.
+var ErrEditUnchanged = errors.New("edit cancelled, no changes made")
+
+// done reports the outcome of an action, which is shown in the status bar.
+func done(action, output string, err error) tea.Msg {
+	return shell.EventRan{Cmd: action, Output: output, Err: err}
+}
+
+// Get shows the object as YAML.
+func (c *Client) Get(ns, resource string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := c.get(context.Background(), ns, resource)
+		if err != nil {
+			return done("get", "", err)
+		}
+		return c.terminal.Page(out)()
+	}
+}
+
+// Describe shows a human readable summary of the object and its events.
+func (c *Client) Describe(ns, resource string) tea.Cmd {
+	return func() tea.Msg {
+		out, err := c.describe(context.Background(), ns, resource)
+		if err != nil {
+			return done("describe", "", err)
+		}
+		return c.terminal.Page(out)()
+	}
+}
+
+// Edit opens the objects with the editor, one after the other, updating each
+// of them once the editor is closed.
+func (c *Client) Edit(ns string, resources ...string) tea.Cmd {
+	cmds := make([]tea.Cmd, 0, len(resources))
+	for _, resource := range resources {
+		cmds = append(cmds, c.edit(ns, resource))
+	}
+	return tea.Sequence(cmds...)
+}
+
+// Delete deletes the objects, with dependents being deleted according to the
+// propagation policy.
+func (c *Client) Delete(ns string, resources ...string) tea.Cmd {
+	return func() tea.Msg {
+		deleted, err := c.forEach(ns, resources, func(ctx context.Context, o object) error {
+			return o.client.Delete(ctx, o.name, metav1.DeleteOptions{PropagationPolicy: &c.propagation})
+		})
+		return done("delete", deleted+" deleted", err)
+	}
+}
+
+// Annotate sets annotations in the `key=value` format, or removes them if in
+// the `key-` format, overwriting existing ones.
+func (c *Client) Annotate(ns string, annotations []string, resources ...string) tea.Cmd {
+	return func() tea.Msg {
+		patch, err := annotationsPatch(annotations)
+		if err != nil {
+			return done("annotate", "", err)
+		}
+		annotated, err := c.forEach(ns, resources, func(ctx context.Context, o object) error {
+			_, err := o.client.Patch(ctx, o.name, types.MergePatchType, patch, metav1.PatchOptions{})
+			return err
+		})
+		return done("annotate", annotated+" annotated", err)
+	}
+}
+
+// forEach runs fn against every resource, returning the ones it succeeded for.
+func (c *Client) forEach(ns string, resources []string, fn func(context.Context, object) error) (string, error) {
+	ok := []string{}
+	errs := []error{}
+	for _, resource := range resources {
+		o, err := c.resolve(ns, resource)
+		if err == nil {
+			err = fn(context.Background(), o)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		ok = append(ok, o.id())
+	}
+	return strings.Join(ok, ", "), errors.Join(errs...)
+}
+
+// annotationsPatch returns a merge patch setting (or removing) annotations.
+func annotationsPatch(annotations []string) ([]byte, error) {
+	values := map[string]any{}
+	for _, a := range annotations {
+		if k, ok := strings.CutSuffix(a, "-"); ok && !strings.Contains(a, "=") {
+			values[k] = nil
+			continue
+		}
+		k, v, ok := strings.Cut(a, "=")
+		if !ok || k == "" {
+			return nil, fmt.Errorf("invalid annotation '%s': must be on the format 'key=value' or 'key-'", a)
+		}
+		values[k] = v
+	}
+	return json.Marshal(map[string]any{"metadata": map[string]any{"annotations": values}})
+}
+
+func (c *Client) get(ctx context.Context, ns, resource string) (string, error) {
+	o, err := c.resolve(ns, resource)
+	if err != nil {
+		return "", err
+	}
+	u, err := o.client.Get(ctx, o.name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	out, err := yaml.Marshal(u.Object)
+	return string(out), err
+}
+
+// edit writes the object into a temporary file, opening it with the editor.
+func (c *Client) edit(ns, resource string) tea.Cmd {
+	return func() tea.Msg {
+		o, err := c.resolve(ns, resource)
+		if err != nil {
+			return done("edit", "", err)
+		}
+		u, err := o.client.Get(context.Background(), o.name, metav1.GetOptions{})
+		if err != nil {
+			return done("edit", "", err)
+		}
+		original, err := yaml.Marshal(u.Object)
+		if err != nil {
+			return done("edit", "", err)
+		}
+
+		f, err := os.CreateTemp("", "xpdig-edit-*.yaml")
+		if err != nil {
+			return done("edit", "", err)
+		}
+		defer f.Close()
+		if _, err := f.Write(original); err != nil {
+			return done("edit", "", err)
+		}
+
+		path := f.Name()
+		return c.terminal.EditFile(path, func(err error) tea.Msg {
+			if err != nil {
+				os.Remove(path)
+				return done("edit", "", err)
+			}
+			// Updates are not done by the terminal callback, as it would block the UI
+			return tea.BatchMsg{func() tea.Msg {
+				defer os.Remove(path)
+				edited, err := os.ReadFile(path)
+				if err != nil {
+					return done("edit", "", err)
+				}
+				if err := c.update(context.Background(), o, u, original, edited); err != nil {
+					return done("edit", "", err)
+				}
+				return done("edit", o.id()+" edited", nil)
+			}}
+		})()
+	}
+}
+
+// update applies an edit, failing if the object changed since it was read,
+// as the update is only accepted for the resource version that was edited.
+func (c *Client) update(ctx context.Context, o object, current *unstructured.Unstructured, original, edited []byte) error {
+	if bytes.Equal(bytes.TrimSpace(original), bytes.TrimSpace(edited)) {
+		return ErrEditUnchanged
+	}
+
+	u := &unstructured.Unstructured{}
+	if err := yaml.Unmarshal(edited, &u.Object); err != nil {
+		return fmt.Errorf("invalid edit: %w", err)
+	}
+	if u.GetName() != current.GetName() || u.GetKind() != current.GetKind() {
+		return errors.New("invalid edit: the kind and name can not be changed")
+	}
+	u.SetResourceVersion(current.GetResourceVersion())
+
+	if _, err := o.client.Update(ctx, u, metav1.UpdateOptions{}); err != nil {
+		if apierrors.IsConflict(err) {
+			return &ErrConflict{Resource: o.id()}
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,212 @@
+// Package kubeapi runs actions against objects through the Kubernetes API,
+// in-process, instead of shelling out to kubectl.
+package kubeapi
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+type terminal interface {
+	Page(content string) tea.Cmd
+	EditFile(path string, fn func(error) tea.Msg) tea.Cmd
+}
+
+// ErrInvalidResource is returned for resources not in the `Kind[.group]/name` format.
+type ErrInvalidResource struct {
+	Resource string
+}
+
+func (e *ErrInvalidResource) Error() string {
+	return fmt.Sprintf("invalid resource '%s': must be on the format '<kind>[.<group>]/<name>'", e.Resource)
+}
+
+// ErrInvalidPropagation is returned for unknown deletion propagation policies.
+type ErrInvalidPropagation struct {
+	Policy string
+}
+
+func (e *ErrInvalidPropagation) Error() string {
+	return fmt.Sprintf("invalid propagation '%s': must be 'background', 'foreground' or 'orphan'", e.Policy)
+}
+
+// ParsePropagation returns the deletion propagation policy, case-insensitive.
+func ParsePropagation(s string) (metav1.DeletionPropagation, error) {
+	for _, p := range []metav1.DeletionPropagation{
+		metav1.DeletePropagationBackground,
+		metav1.DeletePropagationForeground,
+		metav1.DeletePropagationOrphan,
+	} {
+		if strings.EqualFold(s, string(p)) {
+			return p, nil
+		}
+	}
+	return "", &ErrInvalidPropagation{Policy: s}
+}
+
+// Clients are what actions need to reach a cluster.
+type Clients struct {
+	Dynamic dynamic.Interface
+	Mapper  meta.RESTMapper
+	// Namespace is used for namespaced objects when no namespace is given
+	Namespace string
+}
+
+// ClientsFunc returns the clients for a Kubernetes context, with an empty one
+// being the current context.
+type ClientsFunc func(kubectx string) (Clients, error)
+
+type Client struct {
+	kubectx     string
+	terminal    terminal
+	newClients  ClientsFunc
+	propagation metav1.DeletionPropagation
+
+	mu      sync.Mutex
+	clients *Clients
+}
+
+type WithOpt func(*Client)
+
+// WithClients replaces how clients are created, which are taken from the
+// kubeconfig by default.
+func WithClients(fn ClientsFunc) func(*Client) {
+	return func(c *Client) { c.newClients = fn }
+}
+
+// WithPropagation sets how dependents are deleted, in the background by default.
+func WithPropagation(p metav1.DeletionPropagation) func(*Client) {
+	return func(c *Client) { c.propagation = p }
+}
+
+func New(kubectx string, t terminal, opts ...WithOpt) *Client {
+	c := &Client{
+		kubectx:     kubectx,
+		terminal:    t,
+		newClients:  KubeconfigClients,
+		propagation: metav1.DeletePropagationBackground,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// SetContext changes which Kubernetes context actions run against.
+func (c *Client) SetContext(kubectx string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if kubectx != c.kubectx {
+		c.kubectx = kubectx
+		c.clients = nil
+	}
+}
+
+// KubeconfigClients returns the clients of a context from the kubeconfig.
+func KubeconfigClients(kubectx string) (Clients, error) {
+	loader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(),
+		&clientcmd.ConfigOverrides{CurrentContext: kubectx},
+	)
+	cfg, err := loader.ClientConfig()
+	if err != nil {
+		return Clients{}, err
+	}
+	ns, _, err := loader.Namespace()
+	if err != nil {
+		return Clients{}, err
+	}
+
+	dyn, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return Clients{}, err
+	}
+	dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return Clients{}, err
+	}
+
+	return Clients{
+		Dynamic:   dyn,
+		Mapper:    restmapper.NewDeferredDiscoveryRESTMapper(memory.NewMemCacheClient(dc)),
+		Namespace: ns,
+	}, nil
+}
+
+// getClients returns the clients of the current context, created once.
+func (c *Client) getClients() (Clients, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.clients != nil {
+		return *c.clients, nil
+	}
+	clients, err := c.newClients(c.kubectx)
+	if err != nil {
+		return Clients{}, err
+	}
+	c.clients = &clients
+	return clients, nil
+}
+
+// object is a resource resolved against the API.
+type object struct {
+	client  dynamic.ResourceInterface
+	mapping *meta.RESTMapping
+	name    string
+	ns      string
+}
+
+// resolve finds out the API resource of `Kind[.group]/name`, as shown in the
+// trace, with the kind also being allowed to be a resource name (eg: `secrets`).
+func (c *Client) resolve(ns, resource string) (object, error) {
+	typ, name, ok := strings.Cut(resource, "/")
+	if !ok || typ == "" || name == "" {
+		return object{}, &ErrInvalidResource{Resource: resource}
+	}
+
+	clients, err := c.getClients()
+	if err != nil {
+		return object{}, err
+	}
+
+	kind, group, _ := strings.Cut(typ, ".")
+	gvk, err := clients.Mapper.KindFor(schema.GroupVersionResource{Group: group, Resource: strings.ToLower(kind)})
+	if err != nil {
+		return object{}, err
+	}
+	mapping, err := clients.Mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return object{}, err
+	}
+
+	if mapping.Scope.Name() != meta.RESTScopeNameNamespace {
+		return object{client: clients.Dynamic.Resource(mapping.Resource), mapping: mapping, name: name}, nil
+	}
+	if ns == "" {
+		ns = clients.Namespace
+	}
+	return object{
+		client:  clients.Dynamic.Resource(mapping.Resource).Namespace(ns),
+		mapping: mapping,
+		name:    name,
+		ns:      ns,
+	}, nil
+}
+
+// id returns how objects are shown in messages, eg: `objectstorage.test.cloud/name`.
+func (o object) id() string {
+	return o.mapping.Resource.GroupResource().String() + "/" + o.name
+}
@@ -0,0 +1,547 @@
+package kubeapi
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/action/shell"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/google/go-cmp/cmp"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/dynamic"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/yaml"
+)
+
+var (
+	bucketGVK = schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"}
+	claimGVK  = schema.GroupVersionKind{Group: "test.cloud", Version: "v1alpha1", Kind: "ObjectStorage"}
+)
+
+// fakeTerminal keeps what would be shown by the pager, editing files with
+// edit as if it was the editor.
+type fakeTerminal struct {
+	paged  string
+	edited string
+	edit   func(content string) string
+}
+
+func (t *fakeTerminal) Page(content string) tea.Cmd {
+	return func() tea.Msg {
+		t.paged = content
+		return nil
+	}
+}
+
+func (t *fakeTerminal) EditFile(path string, fn func(error) tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		t.edited = path
+		if t.edit == nil {
+			return fn(nil)
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fn(err)
+		}
+		return fn(os.WriteFile(path, []byte(t.edit(string(content))), 0o600))
+	}
+}
+
+func newObject(gvk schema.GroupVersionKind, ns, name string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetGroupVersionKind(gvk)
+	u.SetNamespace(ns)
+	u.SetName(name)
+	u.SetUID(types.UID("uid-" + name))
+	u.SetResourceVersion("1")
+	return u
+}
+
+func newEvent(name, involved, reason string) *unstructured.Unstructured {
+	u := newObject(schema.GroupVersionKind{Version: "v1", Kind: "Event"}, "default", name)
+	u.Object["involvedObject"] = map[string]any{"uid": "uid-" + involved}
+	u.Object["type"] = "Normal"
+	u.Object["reason"] = reason
+	u.Object["message"] = "message of " + reason
+	u.Object["lastTimestamp"] = "2024-01-01T00:00:00Z"
+	return u
+}
+
+func newTestClient(t *testing.T, opts ...WithOpt) (*Client, *dynamicfake.FakeDynamicClient, *fakeTerminal) {
+	t.Helper()
+
+	bucket := newObject(bucketGVK, "", "bucket")
+	bucket.SetLabels(map[string]string{"app": "test"})
+	bucket.Object["spec"] = map[string]any{"forProvider": map[string]any{"region": "eu-west-1"}}
+	bucket.Object["status"] = map[string]any{
+		"atProvider": map[string]any{"arn": "arn:aws:s3:::bucket"},
+		"conditions": []any{map[string]any{"type": "Ready", "status": "True", "reason": "Available"}},
+	}
+	claim := newObject(claimGVK, "default", "claim")
+
+	mapper := meta.NewDefaultRESTMapper(nil)
+	mapper.Add(bucketGVK, meta.RESTScopeRoot)
+	mapper.Add(claimGVK, meta.RESTScopeNamespace)
+
+	dyn := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			{Group: bucketGVK.Group, Version: bucketGVK.Version, Resource: "buckets"}:      "BucketList",
+			{Group: claimGVK.Group, Version: claimGVK.Version, Resource: "objectstorages"}: "ObjectStorageList",
+			eventsResource: "EventList",
+		},
+		bucket, claim, newEvent("bucket.1", "bucket", "Created"), newEvent("claim.1", "claim", "Bound"),
+	)
+
+	term := &fakeTerminal{}
+	opts = append([]WithOpt{WithClients(func(string) (Clients, error) {
+		return Clients{Dynamic: dyn, Mapper: mapper, Namespace: "default"}, nil
+	})}, opts...)
+	return New("", term, opts...), dyn, term
+}
+
+// deleteRecorder keeps the propagation policy objects were deleted with.
+type deleteRecorder struct {
+	dynamic.Interface
+	propagation *metav1.DeletionPropagation
+}
+
+func (r deleteRecorder) Resource(gvr schema.GroupVersionResource) dynamic.NamespaceableResourceInterface {
+	return recordedResource{NamespaceableResourceInterface: r.Interface.Resource(gvr), propagation: r.propagation}
+}
+
+type recordedResource struct {
+	dynamic.NamespaceableResourceInterface
+	propagation *metav1.DeletionPropagation
+}
+
+func (r recordedResource) Namespace(ns string) dynamic.ResourceInterface {
+	return recordedNamespacedResource{ResourceInterface: r.NamespaceableResourceInterface.Namespace(ns), propagation: r.propagation}
+}
+
+func (r recordedResource) Delete(ctx context.Context, name string, opts metav1.DeleteOptions, subresources ...string) error {
+	*r.propagation = *opts.PropagationPolicy
+	return r.NamespaceableResourceInterface.Delete(ctx, name, opts, subresources...)
+}
+
+type recordedNamespacedResource struct {
+	dynamic.ResourceInterface
+	propagation *metav1.DeletionPropagation
+}
+
+func (r recordedNamespacedResource) Delete(ctx context.Context, name string, opts metav1.DeleteOptions, subresources ...string) error {
+	*r.propagation = *opts.PropagationPolicy
+	return r.ResourceInterface.Delete(ctx, name, opts, subresources...)
+}
+
+func errString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+func TestGet(t *testing.T) {
+	type args struct {
+		ns       string
+		resource string
+	}
+
+	type want struct {
+		name string
+		err  string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ClusterScoped": {
+			reason: "Should show cluster scoped objects as YAML",
+			args:   args{resource: "Bucket.s3.aws.upbound.io/bucket"},
+			want:   want{name: "bucket"},
+		},
+		"DefaultNamespace": {
+			reason: "Should look for namespaced objects in the default namespace if none is given",
+			args:   args{resource: "ObjectStorage.test.cloud/claim"},
+			want:   want{name: "claim"},
+		},
+		"InvalidResource": {
+			reason: "Should fail for resources not in the Kind/name format",
+			args:   args{resource: "Bucket"},
+			want:   want{err: (&ErrInvalidResource{Resource: "Bucket"}).Error()},
+		},
+		"NotFound": {
+			reason: "Should fail for objects that do not exist",
+			args:   args{ns: "other", resource: "ObjectStorage.test.cloud/claim"},
+			want:   want{err: `objectstorages.test.cloud "claim" not found`},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, _, term := newTestClient(t)
+			msg := c.Get(tc.args.ns, tc.args.resource)()
+
+			var err error
+			if ran, ok := msg.(shell.EventRan); ok {
+				err = ran.Err
+			}
+			if diff := cmp.Diff(tc.want.err, errString(err)); diff != "" {
+				t.Fatalf("\n%s\nGet(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if tc.want.name == "" {
+				return
+			}
+
+			u := &unstructured.Unstructured{}
+			if err := yaml.Unmarshal([]byte(term.paged), &u.Object); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tc.want.name, u.GetName()); diff != "" {
+				t.Errorf("\n%s\nGet(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	c, _, term := newTestClient(t)
+	if msg := c.Describe("", "Bucket.s3.aws.upbound.io/bucket")(); msg != nil {
+		t.Fatalf("Describe(...): unexpected message %+v", msg)
+	}
+
+	for _, want := range []string{
+		"Kind:         Bucket (s3.aws.upbound.io/v1beta1)",
+		"Labels:       app=test",
+		"Ready  True    Available",
+		"region: eu-west-1",
+		"arn: arn:aws:s3:::bucket",
+		"message of Created",
+	} {
+		if !strings.Contains(term.paged, want) {
+			t.Errorf("Describe(...): want %q in:\n%s", want, term.paged)
+		}
+	}
+	if strings.Contains(term.paged, "message of Bound") {
+		t.Errorf("Describe(...): want only events about the object in:\n%s", term.paged)
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	type args struct {
+		annotations []string
+	}
+
+	type want struct {
+		annotations map[string]string
+		err         string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Set": {
+			reason: "Should set annotations, overwriting existing ones",
+			args:   args{annotations: []string{"a=1", "keep=2"}},
+			want:   want{annotations: map[string]string{"a": "1", "keep": "2", "remove": "me"}},
+		},
+		"Remove": {
+			reason: "Should remove annotations in the key- format",
+			args:   args{annotations: []string{"remove-"}},
+			want:   want{annotations: map[string]string{"keep": "me"}},
+		},
+		"Invalid": {
+			reason: "Should fail for annotations not in the key=value or key- format",
+			args:   args{annotations: []string{"a"}},
+			want: want{
+				annotations: map[string]string{"keep": "me", "remove": "me"},
+				err:         "invalid annotation 'a': must be on the format 'key=value' or 'key-'",
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, dyn, _ := newTestClient(t)
+			gvr := schema.GroupVersionResource{Group: claimGVK.Group, Version: claimGVK.Version, Resource: "objectstorages"}
+			claim, _ := dyn.Resource(gvr).Namespace("default").Get(context.Background(), "claim", metav1.GetOptions{})
+			claim.SetAnnotations(map[string]string{"keep": "me", "remove": "me"})
+			if _, err := dyn.Resource(gvr).Namespace("default").Update(context.Background(), claim, metav1.UpdateOptions{}); err != nil {
+				t.Fatal(err)
+			}
+
+			ran, _ := c.Annotate("default", tc.args.annotations, "ObjectStorage.test.cloud/claim")().(shell.EventRan)
+			if diff := cmp.Diff(tc.want.err, errString(ran.Err)); diff != "" {
+				t.Fatalf("\n%s\nAnnotate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			got, _ := dyn.Resource(gvr).Namespace("default").Get(context.Background(), "claim", metav1.GetOptions{})
+			if diff := cmp.Diff(tc.want.annotations, got.GetAnnotations()); diff != "" {
+				t.Errorf("\n%s\nAnnotate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestDelete(t *testing.T) {
+	type args struct {
+		opts      []WithOpt
+		resources []string
+	}
+
+	type want struct {
+		propagation metav1.DeletionPropagation
+		output      string
+		err         string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Background": {
+			reason: "Should delete dependents in the background by default",
+			args:   args{resources: []string{"Bucket.s3.aws.upbound.io/bucket"}},
+			want:   want{propagation: metav1.DeletePropagationBackground, output: "buckets.s3.aws.upbound.io/bucket deleted"},
+		},
+		"Foreground": {
+			reason: "Should delete dependents according to the propagation policy",
+			args: args{
+				opts:      []WithOpt{WithPropagation(metav1.DeletePropagationForeground)},
+				resources: []string{"Bucket.s3.aws.upbound.io/bucket", "ObjectStorage.test.cloud/claim"},
+			},
+			want: want{
+				propagation: metav1.DeletePropagationForeground,
+				output:      "buckets.s3.aws.upbound.io/bucket, objectstorages.test.cloud/claim deleted",
+			},
+		},
+		"PartialFailure": {
+			reason: "Should delete what exists, failing for the rest",
+			args:   args{resources: []string{"Bucket.s3.aws.upbound.io/missing", "Bucket.s3.aws.upbound.io/bucket"}},
+			want: want{
+				propagation: metav1.DeletePropagationBackground,
+				output:      "buckets.s3.aws.upbound.io/bucket deleted",
+				err:         `buckets.s3.aws.upbound.io "missing" not found`,
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, dyn, _ := newTestClient(t, tc.args.opts...)
+			// The fake client does not keep the delete options, so these are recorded
+			propagation := metav1.DeletionPropagation("")
+			newClients := c.newClients
+			c.newClients = func(kubectx string) (Clients, error) {
+				clients, err := newClients(kubectx)
+				clients.Dynamic = deleteRecorder{Interface: dyn, propagation: &propagation}
+				return clients, err
+			}
+
+			ran, _ := c.Delete("", tc.args.resources...)().(shell.EventRan)
+			if diff := cmp.Diff(tc.want.err, errString(ran.Err)); diff != "" {
+				t.Errorf("\n%s\nDelete(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.output, ran.Output); diff != "" {
+				t.Errorf("\n%s\nDelete(...): -want output, +got output:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.propagation, propagation); diff != "" {
+				t.Errorf("\n%s\nDelete(...): -want propagation, +got propagation:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestEdit(t *testing.T) {
+	type args struct {
+		edit func(content string) string
+	}
+
+	type want struct {
+		region string
+		output string
+		err    string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Edited": {
+			reason: "Should update the object with what was saved in the editor",
+			args: args{edit: func(content string) string {
+				return strings.Replace(content, "eu-west-1", "us-east-1", 1)
+			}},
+			want: want{region: "us-east-1", output: "buckets.s3.aws.upbound.io/bucket edited"},
+		},
+		"Unchanged": {
+			reason: "Should not update the object if the editor was closed without changes",
+			args:   args{edit: func(content string) string { return content }},
+			want:   want{region: "eu-west-1", err: ErrEditUnchanged.Error()},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, _, term := newTestClient(t)
+			term.edit = tc.args.edit
+
+			// Edit runs this once per resource, with the update done apart from the
+			// editor callback, as a batch
+			batch, ok := c.edit("", "Bucket.s3.aws.upbound.io/bucket")().(tea.BatchMsg)
+			if !ok || len(batch) != 1 {
+				t.Fatalf("\n%s\nedit(...): want a single command to update the object, got %#v", tc.reason, batch)
+			}
+			ran, _ := batch[0]().(shell.EventRan)
+			if diff := cmp.Diff(tc.want.err, errString(ran.Err)); diff != "" {
+				t.Errorf("\n%s\nEdit(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.output, ran.Output); diff != "" {
+				t.Errorf("\n%s\nEdit(...): -want output, +got output:\n%s", tc.reason, diff)
+			}
+
+			o, err := c.resolve("", "Bucket.s3.aws.upbound.io/bucket")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, _ := o.client.Get(context.Background(), o.name, metav1.GetOptions{})
+			region, _, _ := unstructured.NestedString(got.Object, "spec", "forProvider", "region")
+			if diff := cmp.Diff(tc.want.region, region); diff != "" {
+				t.Errorf("\n%s\nEdit(...): -want, +got:\n%s", tc.reason, diff)
+			}
+
+			if _, err := os.Stat(term.edited); !os.IsNotExist(err) {
+				t.Errorf("\n%s\nEdit(...): want the edited file %q removed, got error %v", tc.reason, term.edited, err)
+			}
+		})
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	type args struct {
+		edit     func(u *unstructured.Unstructured)
+		conflict bool
+	}
+
+	type want struct {
+		region string
+		err    string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Edited": {
+			reason: "Should update the object with the edit",
+			args: args{edit: func(u *unstructured.Unstructured) {
+				_ = unstructured.SetNestedField(u.Object, "us-east-1", "spec", "forProvider", "region")
+			}},
+			want: want{region: "us-east-1"},
+		},
+		"Unchanged": {
+			reason: "Should not update the object if the edit changed nothing",
+			args:   args{edit: func(*unstructured.Unstructured) {}},
+			want:   want{region: "eu-west-1", err: ErrEditUnchanged.Error()},
+		},
+		"Renamed": {
+			reason: "Should not update another object than the one edited",
+			args:   args{edit: func(u *unstructured.Unstructured) { u.SetName("other") }},
+			want:   want{region: "eu-west-1", err: "invalid edit: the kind and name can not be changed"},
+		},
+		"Conflict": {
+			reason: "Should not update the object if it changed while being edited",
+			args: args{
+				edit: func(u *unstructured.Unstructured) {
+					_ = unstructured.SetNestedField(u.Object, "us-east-1", "spec", "forProvider", "region")
+				},
+				conflict: true,
+			},
+			want: want{
+				region: "eu-west-1",
+				err:    (&ErrConflict{Resource: "buckets.s3.aws.upbound.io/bucket"}).Error(),
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, dyn, _ := newTestClient(t)
+			if tc.args.conflict {
+				dyn.PrependReactor("update", "buckets", func(action clienttesting.Action) (bool, runtime.Object, error) {
+					gr := action.GetResource().GroupResource()
+					return true, nil, apierrors.NewConflict(gr, "bucket", nil)
+				})
+			}
+
+			o, err := c.resolve("", "Bucket.s3.aws.upbound.io/bucket")
+			if err != nil {
+				t.Fatal(err)
+			}
+			current, err := o.client.Get(context.Background(), o.name, metav1.GetOptions{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			original, _ := yaml.Marshal(current.Object)
+			edited := current.DeepCopy()
+			tc.args.edit(edited)
+			out, _ := yaml.Marshal(edited.Object)
+
+			err = c.update(context.Background(), o, current, original, out)
+			if diff := cmp.Diff(tc.want.err, errString(err)); diff != "" {
+				t.Errorf("\n%s\nupdate(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+
+			got, _ := o.client.Get(context.Background(), o.name, metav1.GetOptions{})
+			region, _, _ := unstructured.NestedString(got.Object, "spec", "forProvider", "region")
+			if diff := cmp.Diff(tc.want.region, region); diff != "" {
+				t.Errorf("\n%s\nupdate(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
+
+func TestParsePropagation(t *testing.T) {
+	type want struct {
+		propagation metav1.DeletionPropagation
+		err         string
+	}
+	tests := map[string]struct {
+		reason string
+		args   string
+		want   want
+	}{
+		"CaseInsensitive": {
+			reason: "Should accept policies in any case",
+			args:   "foreground",
+			want:   want{propagation: metav1.DeletePropagationForeground},
+		},
+		"Unknown": {
+			reason: "Should fail for unknown policies",
+			args:   "later",
+			want:   want{err: (&ErrInvalidPropagation{Policy: "later"}).Error()},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := ParsePropagation(tc.args)
+			if diff := cmp.Diff(tc.want.err, errString(err)); diff != "" {
+				t.Errorf("\n%s\nParsePropagation(...): -want error, +got error:\n%s", tc.reason, diff)
+			}
+			if diff := cmp.Diff(tc.want.propagation, got); diff != "" {
+				t.Errorf("\n%s\nParsePropagation(...): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,186 @@
+package kubeapi
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/yaml"
+)
+
+var eventsResource = schema.GroupVersionResource{Version: "v1", Resource: "events"}
+
+// describe renders the object similarly to `kubectl describe`: its metadata,
+// conditions, spec, the rest of its status and the events about it.
+func (c *Client) describe(ctx context.Context, ns, resource string) (string, error) {
+	o, err := c.resolve(ns, resource)
+	if err != nil {
+		return "", err
+	}
+	u, err := o.client.Get(ctx, o.name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	events, err := c.events(ctx, u)
+	if err != nil {
+		return "", err
+	}
+
+	b := &strings.Builder{}
+	w := tabwriter.NewWriter(b, 0, 8, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", u.GetName())
+	if u.GetNamespace() != "" {
+		fmt.Fprintf(w, "Namespace:\t%s\n", u.GetNamespace())
+	}
+	fmt.Fprintf(w, "Kind:\t%s (%s)\n", u.GetKind(), u.GetAPIVersion())
+	writeList(w, "Labels", keyValues(u.GetLabels()))
+	writeList(w, "Annotations", keyValues(u.GetAnnotations()))
+	fmt.Fprintf(w, "Created:\t%s\n", u.GetCreationTimestamp().UTC().Format(timeFormat))
+	if t := u.GetDeletionTimestamp(); t != nil {
+		fmt.Fprintf(w, "Deleting:\t%s\n", t.UTC().Format(timeFormat))
+	}
+	owners := []string{}
+	for _, ref := range u.GetOwnerReferences() {
+		owners = append(owners, ref.Kind+"/"+ref.Name)
+	}
+	writeList(w, "Owners", owners)
+	writeList(w, "Finalizers", u.GetFinalizers())
+	if err := w.Flush(); err != nil {
+		return "", err
+	}
+
+	status, _, _ := unstructured.NestedMap(u.Object, "status")
+	conditions, _, _ := unstructured.NestedSlice(status, "conditions")
+	delete(status, "conditions")
+	spec, _, _ := unstructured.NestedMap(u.Object, "spec")
+
+	writeConditions(b, conditions)
+	if err := writeYAML(b, "Spec", spec); err != nil {
+		return "", err
+	}
+	if err := writeYAML(b, "Status", status); err != nil {
+		return "", err
+	}
+	writeEvents(b, events)
+	return b.String(), nil
+}
+
+const timeFormat = "2006-01-02 15:04:05 MST"
+
+// events returns the events about the object, oldest first.
+func (c *Client) events(ctx context.Context, u *unstructured.Unstructured) ([]unstructured.Unstructured, error) {
+	clients, err := c.getClients()
+	if err != nil {
+		return nil, err
+	}
+	list, err := clients.Dynamic.Resource(eventsResource).Namespace(u.GetNamespace()).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.uid", string(u.GetUID())).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// The field selector is not honoured everywhere (eg: by fake clients)
+	events := slices.DeleteFunc(list.Items, func(e unstructured.Unstructured) bool {
+		uid, _, _ := unstructured.NestedString(e.Object, "involvedObject", "uid")
+		return uid != string(u.GetUID())
+	})
+	slices.SortStableFunc(events, func(a, b unstructured.Unstructured) int {
+		return strings.Compare(lastSeen(a), lastSeen(b))
+	})
+	return events, nil
+}
+
+// lastSeen returns when the event last happened, as events set either field.
+func lastSeen(e unstructured.Unstructured) string {
+	if t, _, _ := unstructured.NestedString(e.Object, "lastTimestamp"); t != "" {
+		return t
+	}
+	t, _, _ := unstructured.NestedString(e.Object, "eventTime")
+	return t
+}
+
+func keyValues(m map[string]string) []string {
+	out := make([]string, 0, len(m))
+	for k, v := range m {
+		out = append(out, k+"="+v)
+	}
+	slices.Sort(out)
+	return out
+}
+
+// writeList writes one value per line, all aligned with the first one.
+func writeList(w *tabwriter.Writer, title string, values []string) {
+	if len(values) == 0 {
+		fmt.Fprintf(w, "%s:\t<none>\n", title)
+		return
+	}
+	for i, v := range values {
+		if i == 0 {
+			fmt.Fprintf(w, "%s:\t%s\n", title, v)
+			continue
+		}
+		fmt.Fprintf(w, "\t%s\n", v)
+	}
+}
+
+func writeConditions(b *strings.Builder, conditions []any) {
+	if len(conditions) == 0 {
+		return
+	}
+	b.WriteString("Conditions:\n")
+	w := tabwriter.NewWriter(b, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "  TYPE\tSTATUS\tREASON\tLAST TRANSITION\tMESSAGE")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if !ok {
+			continue
+		}
+		fmt.Fprintf(w, "  %v\t%v\t%v\t%v\t%v\n",
+			cond["type"], cond["status"], valueOr(cond["reason"], ""), valueOr(cond["lastTransitionTime"], ""), valueOr(cond["message"], ""))
+	}
+	_ = w.Flush()
+}
+
+func writeYAML(b *strings.Builder, title string, v map[string]any) error {
+	if len(v) == 0 {
+		return nil
+	}
+	out, err := yaml.Marshal(v)
+	if err != nil {
+		return err
+	}
+	b.WriteString(title + ":\n")
+	for _, line := range strings.Split(strings.TrimSuffix(string(out), "\n"), "\n") {
+		b.WriteString("  " + line + "\n")
+	}
+	return nil
+}
+
+func writeEvents(b *strings.Builder, events []unstructured.Unstructured) {
+	if len(events) == 0 {
+		b.WriteString("Events:  <none>\n")
+		return
+	}
+	b.WriteString("Events:\n")
+	w := tabwriter.NewWriter(b, 0, 8, 2, ' ', 0)
+	fmt.Fprintln(w, "  TYPE\tREASON\tLAST SEEN\tCOUNT\tMESSAGE")
+	for _, e := range events {
+		fmt.Fprintf(w, "  %v\t%v\t%s\t%v\t%v\n",
+			e.Object["type"], e.Object["reason"], lastSeen(e), valueOr(e.Object["count"], 1), e.Object["message"])
+	}
+	_ = w.Flush()
+}
+
+func valueOr(v, fallback any) any {
+	if v == nil {
+		return fallback
+	}
+	return v
+}
@@ -7,6 +7,7 @@ import (
 type shell interface {
 	Exec(c string, args ...string) tea.Cmd
 	Pager(c string, args ...string) tea.Cmd
+	Run(c string, args ...string) tea.Cmd
 }
 
 type Cmd struct {
@@ -18,8 +19,11 @@ func New(kubectx string, s shell) *Cmd {
 	return &Cmd{kubectx: kubectx, shell: s}
 }
 
-func (k *Cmd) Edit(ns, resource string) tea.Cmd {
-	args := []string{"edit", resource}
+// SetContext changes which Kubernetes context commands run against.
+func (k *Cmd) SetContext(kubectx string) { k.kubectx = kubectx }
+
+func (k *Cmd) Edit(ns string, resources ...string) tea.Cmd {
+	args := append([]string{"edit"}, resources...)
 	if ns != "" {
 		args = append(args, "-n", ns)
 	}
@@ -51,8 +55,8 @@ func (k *Cmd) Get(ns, resource string) tea.Cmd {
 	return k.shell.Pager("kubectl", args...)
 }
 
-func (k *Cmd) Delete(ns, resource string) tea.Cmd {
-	args := []string{"delete", resource}
+func (k *Cmd) Delete(ns string, resources ...string) tea.Cmd {
+	args := append([]string{"delete"}, resources...)
 	if ns != "" {
 		args = append(args, "-n", ns, "--wait", "false")
 	}
@@ -61,3 +65,17 @@ func (k *Cmd) Delete(ns, resource string) tea.Cmd {
 	}
 	return k.shell.Exec("kubectl", args...)
 }
+
+// Annotate sets annotations in the `key=value` format, or removes them if in
+// the `key-` format, overwriting existing ones.
+func (k *Cmd) Annotate(ns string, annotations []string, resources ...string) tea.Cmd {
+	args := append([]string{"annotate", "--overwrite"}, resources...)
+	args = append(args, annotations...)
+	if ns != "" {
+		args = append(args, "-n", ns)
+	}
+	if k.kubectx != "" {
+		args = append(args, "--context", k.kubectx)
+	}
+	return k.shell.Run("kubectl", args...)
+}
@@ -10,12 +10,38 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// EventRan is sent once a command started by Run finishes, or once the pager
+// fails to show content.
+type EventRan struct {
+	Cmd    string
+	Output string
+	Err    error
+}
+
 type Cmd struct {
 	logger *slog.Logger
+	pager  string
+	editor string
+}
+
+type WithOpt func(*Cmd)
+
+// WithPager sets the pager used instead of $PAGER, if not empty.
+func WithPager(p string) func(*Cmd) {
+	return func(s *Cmd) { s.pager = p }
 }
 
-func New(logger *slog.Logger) *Cmd {
-	return &Cmd{logger: logger}
+// WithEditor sets the editor used instead of $EDITOR, if not empty.
+func WithEditor(e string) func(*Cmd) {
+	return func(s *Cmd) { s.editor = e }
+}
+
+func New(logger *slog.Logger, opts ...WithOpt) *Cmd {
+	s := &Cmd{logger: logger}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
 }
 
 func (s *Cmd) Exec(c string, args ...string) tea.Cmd {
@@ -23,7 +49,7 @@ func (s *Cmd) Exec(c string, args ...string) tea.Cmd {
 
 	cmd := exec.Command(c, args...)
 	// Inherit environment so $EDITOR is respected
-	cmd.Env = os.Environ()
+	cmd.Env = s.environ()
 	// Attach to the user's terminal
 	// cmd.Stdin = os.Stdin
 	// cmd.Stdout = os.Stdout
@@ -36,7 +62,53 @@ func (s *Cmd) Exec(c string, args ...string) tea.Cmd {
 
 func (s *Cmd) Pager(c string, args ...string) tea.Cmd {
 	cmd := c + " " + strings.Join(args, " ")
-	pager := os.Getenv("PAGER")
+	viewCmd := fmt.Sprintf("%s | %s", cmd, s.pagerCmd())
+
+	return s.Exec(os.Getenv("SHELL"), "-c", viewCmd)
+}
+
+// Page shows the content with the pager, without going through $SHELL.
+func (s *Cmd) Page(content string) tea.Cmd {
+	pager := strings.Fields(s.pagerCmd())
+	s.logger.Info("paging content", "cmd", pager)
+
+	//nolint // trust the user input
+	cmd := exec.Command(pager[0], pager[1:]...)
+	cmd.Env = s.environ()
+	cmd.Stdin = strings.NewReader(content)
+
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		if err != nil {
+			return EventRan{Cmd: strings.Join(pager, " "), Err: fmt.Errorf("%s: %w", pager[0], err)}
+		}
+		return nil
+	})
+}
+
+// EditFile opens the file with the editor, calling fn once it is closed.
+func (s *Cmd) EditFile(path string, fn func(error) tea.Msg) tea.Cmd {
+	editor := s.editor
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+	args := append(strings.Fields(editor), path)
+	s.logger.Info("editing file", "cmd", args)
+
+	//nolint // trust the user input
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Env = s.environ()
+
+	return tea.ExecProcess(cmd, fn)
+}
+
+func (s *Cmd) pagerCmd() string {
+	pager := s.pager
+	if pager == "" {
+		pager = os.Getenv("PAGER")
+	}
 	// Default for those who never thought about it
 	if pager == "" {
 		pager = "less"
@@ -46,7 +118,31 @@ func (s *Cmd) Pager(c string, args ...string) tea.Cmd {
 	if pager == "bat" {
 		pager = "bat -l yaml --paging always"
 	}
-	viewCmd := fmt.Sprintf("%s | %s", cmd, pager)
+	return pager
+}
 
-	return s.Exec(os.Getenv("SHELL"), "-c", viewCmd)
+// Run executes a command in the background, without giving it the terminal.
+func (s *Cmd) Run(c string, args ...string) tea.Cmd {
+	s.logger.Info("running shell", "cmd", c, "args", args)
+
+	return func() tea.Msg {
+		cmd := exec.Command(c, args...)
+		cmd.Env = s.environ()
+
+		out, err := cmd.CombinedOutput()
+		output := strings.TrimSpace(string(out))
+		if err != nil {
+			err = fmt.Errorf("%s: %w: %s", c, err, output)
+		}
+		return EventRan{Cmd: c + " " + strings.Join(args, " "), Output: output, Err: err}
+	}
+}
+
+// environ returns the environment, overriding the editor if configured.
+func (s *Cmd) environ() []string {
+	env := os.Environ()
+	if s.editor != "" {
+		env = append(env, "EDITOR="+s.editor, "KUBE_EDITOR="+s.editor)
+	}
+	return env
 }
@@ -2,11 +2,21 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"log/slog"
+	"maps"
 	"reflect"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/atotto/clipboard"
+	"github.com/brunoluiz/xpdig/internal/bubbles/action/shell"
 	"github.com/brunoluiz/xpdig/internal/bubbles/component/navigator"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/popup"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/statusbar"
+	navigatorpane "github.com/brunoluiz/xpdig/internal/bubbles/layout/xpnavigator"
 	"github.com/brunoluiz/xpdig/internal/ds"
 	"github.com/brunoluiz/xpdig/internal/xplane"
 	"github.com/charmbracelet/bubbles/key"
@@ -22,20 +32,53 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		})
 	}
 
-	var cmd tea.Cmd
 	switch msg := msg.(type) {
+	case tabMsg:
+		i := m.tabIndex(msg.id)
+		if i < 0 {
+			// The tab was closed in the meantime
+			return m, nil
+		}
+		if isRuntimeMsg(msg.msg) {
+			return m, func() tea.Msg { return msg.msg }
+		}
+		return m.onTabMsg(i, msg.msg)
 	case tea.WindowSizeMsg:
 		return m, m.onResize(msg)
 	case error:
 		m.setIrrecoverableError(msg)
 		return m, nil
-	case *xplane.Resource:
-		m.navigator, cmd = m.navigator.Update(msg)
-		return m, cmd
+	case eventWatchTick:
+		return m, m.onWatch()
 	case tea.KeyMsg:
-		cmd = m.onKey(msg)
+		if cmd, ok := m.onKey(msg); ok {
+			return m, cmd
+		}
+	case tea.MouseMsg:
+		return m.onMouse(msg)
+	}
+
+	// Messages which are not from a tab (eg: keys) are for the active one
+	return m.onTabMsg(m.active, msg)
+}
+
+// onTabMsg handles a message sent by (or meant to) the tab at index i.
+func (m Model) onTabMsg(i int, msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case error:
+		m.onTabError(i, msg)
+		return m, nil
 	case navigator.EventQuitted:
 		return m, tea.Interrupt
+	case navigatorpane.EventOpenTab:
+		return m, m.onOpenTab(msg.Target)
+	case navigatorpane.EventCloseTab:
+		return m, m.onCloseTab()
+	case navigatorpane.EventTargetChanged:
+		if i == m.active {
+			m.kubectl.SetContext(msg.Target.Context)
+		}
 	case navigator.EventItemGet:
 		trace, ok := msg.Data.(*xplane.Resource)
 		if !ok {
@@ -44,22 +87,29 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		ns, _ := ds.GetPath[string](trace.Unstructured.Object, "metadata", "namespace")
 		return m, tea.Batch(tea.HideCursor, m.kubectl.Get(ns, msg.ID))
 	case navigator.EventItemEdit:
-		trace, ok := msg.Data.(*xplane.Resource)
-		if !ok {
-			return m, nil
-		}
-		ns, _ := ds.GetPath[string](trace.Unstructured.Object, "metadata", "namespace")
-		return m, tea.Batch(tea.HideCursor, m.kubectl.Edit(ns, msg.ID))
+		return m, tea.Batch(tea.HideCursor, forEachNamespace(msg.Items, m.kubectl.Edit))
 	case navigator.EventItemDelete:
-		trace, ok := msg.Data.(*xplane.Resource)
-		if !ok {
-			return m, nil
-		}
-		ns, _ := ds.GetPath[string](trace.Unstructured.Object, "metadata", "namespace")
-		return m, tea.Batch(tea.HideCursor, m.kubectl.Delete(ns, msg.ID))
+		return m, tea.Batch(tea.HideCursor, forEachNamespace(msg.Items, m.kubectl.Delete))
+	case navigator.EventItemAnnotate:
+		return m, forEachNamespace(msg.Items, func(ns string, resources ...string) tea.Cmd {
+			return m.kubectl.Annotate(ns, msg.Annotations, resources...)
+		})
+	case navigator.EventItemPause:
+		return m, m.onPause(i, msg)
+	case navigator.EventItemReconcile:
+		return m, m.onReconcile(i, msg)
 	case navigator.EventItemCopied:
+		ids := make([]string, 0, len(msg.Items))
+		for _, item := range msg.Items {
+			ids = append(ids, item.ID)
+		}
 		//nolint // ignore errors
-		clipboard.WriteAll(msg.ID)
+		clipboard.WriteAll(strings.Join(ids, "\n"))
+	case popup.EventCopied:
+		//nolint // ignore errors
+		clipboard.WriteAll(msg.Text)
+	case navigatorpane.EventDescribeReference:
+		return m, tea.Batch(tea.HideCursor, m.kubectl.Describe(msg.Reference.Namespace, msg.Reference.ID()))
 	case navigator.EventItemDescribe:
 		trace, ok := msg.Data.(*xplane.Resource)
 		if !ok {
@@ -69,37 +119,135 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(tea.HideCursor, m.kubectl.Describe(ns, msg.ID))
 	}
 
-	switch m.pane {
-	case PaneNavigator:
-		var navigatorCmd, statusCmd tea.Cmd
-		m.navigator, navigatorCmd = m.navigator.Update(msg)
-
-		return m, tea.Batch(cmd, statusCmd, navigatorCmd)
-	case PaneIrrecoverableError:
+	if m.pane != PaneNavigator || m.tabs[i].err != nil {
 		return m, cmd
 	}
-
-	return m, cmd
+	return m, tea.Batch(cmd, m.updateTab(i, msg))
 }
 
 func (m *Model) onResize(msg tea.WindowSizeMsg) tea.Cmd {
-	var navigatorCmd, viewerCmd tea.Cmd
-	m.navigator, navigatorCmd = m.navigator.Update(msg)
-
-	return tea.Batch(navigatorCmd, viewerCmd)
+	m.width = msg.Width
+	m.height = msg.Height
+	return m.resizeTabs()
 }
 
-func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
-	//nolint
+// onKey handles the app keys, returning if the key was handled. Tab keys are
+// left to the active tab while it takes keys (eg: typing a search).
+func (m *Model) onKey(msg tea.KeyMsg) (tea.Cmd, bool) {
 	switch {
 	case key.Matches(msg, m.keyMap.Quit):
-		return tea.Interrupt
+		return tea.Interrupt, true
 	// Only used in case there was a failure that requires an exit
-	case key.Matches(msg, m.keyMap.FailQuit):
-		if m.err != nil {
-			return tea.Interrupt
+	case key.Matches(msg, m.keyMap.FailQuit) && m.err != nil:
+		return tea.Interrupt, true
+	}
+
+	if m.pane != PaneNavigator || (m.tabs[m.active].err == nil && m.tabs[m.active].navigator.CapturesKeys()) {
+		return nil, false
+	}
+
+	switch {
+	case key.Matches(msg, m.keyMap.NextTab):
+		m.switchTab((m.active + 1) % len(m.tabs))
+	case key.Matches(msg, m.keyMap.PrevTab):
+		m.switchTab((m.active + len(m.tabs) - 1) % len(m.tabs))
+	case key.Matches(msg, m.keyMap.GoToTab):
+		m.switchTab(int(msg.String()[len(msg.String())-1] - '1'))
+	case key.Matches(msg, m.keyMap.CloseTab):
+		return m.onCloseTab(), true
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// onPause pauses (or resumes) the reconciliation of items.
+func (m Model) onPause(i int, msg navigator.EventItemPause) tea.Cmd {
+	annotation, message := xplane.AnnotationPaused+"=true", "paused "
+	if !msg.Paused {
+		annotation, message = xplane.AnnotationPaused+"-", "resumed "
+	}
+	if len(msg.Items) == 1 {
+		message += msg.Items[0].ID
+	} else {
+		message += fmt.Sprintf("%d objects", len(msg.Items))
+	}
+
+	return m.annotateAndRefresh(i, msg.Items, annotation, 0, func(err error) tea.Msg {
+		if err != nil {
+			return statusbar.EventToast{Err: err}
+		}
+		return statusbar.EventToast{Message: message}
+	})
+}
+
+// reconcileWait is how long controllers get to reconcile the items before
+// their conditions are compared with the ones from before.
+const reconcileWait = 3 * time.Second
+
+// onReconcile changes an annotation of the items, so their controllers
+// reconcile them straight away instead of waiting for their backoff.
+func (m Model) onReconcile(i int, msg navigator.EventItemReconcile) tea.Cmd {
+	annotation := xplane.AnnotationReconcileRequestedAt + "=" + time.Now().UTC().Format(time.RFC3339Nano)
+	items := msg.Items
+	return m.annotateAndRefresh(i, items, annotation, reconcileWait, func(err error) tea.Msg {
+		return navigatorpane.EventReconciled{Items: items, Err: err}
+	})
+}
+
+// annotateAndRefresh annotates the items and loads the trace of their tab
+// again, so the change shows up straight away. If wait is set, the trace is
+// loaded once more after it, giving controllers time to act on the change.
+// Only then the message returned by done is sent (eg: toasts, as any update
+// clears them).
+func (m Model) annotateAndRefresh(i int, items []navigator.Item, annotation string, wait time.Duration, done func(error) tea.Msg) tea.Cmd {
+	cmds := perNamespace(items, func(ns string, resources ...string) tea.Cmd {
+		return m.kubectl.Annotate(ns, []string{annotation}, resources...)
+	})
+	errs := []error{}
+	annotate := func() tea.Msg {
+		for _, cmd := range cmds {
+			if ran, ok := cmd().(shell.EventRan); ok && ran.Err != nil {
+				errs = append(errs, ran.Err)
+			}
+		}
+		return nil
+	}
+
+	id := m.tabs[i].id
+	steps := []tea.Cmd{annotate, wrap(id, m.tabs[i].navigator.Refresh())}
+	if wait > 0 {
+		sleep := func() tea.Msg {
+			time.Sleep(wait)
+			return nil
 		}
+		steps = append(steps, sleep, wrap(id, m.tabs[i].navigator.Refresh()))
 	}
+	steps = append(steps, wrap(id, func() tea.Msg { return done(errors.Join(errs...)) }))
+	return tea.Sequence(steps...)
+}
 
-	return nil
+// forEachNamespace runs a command once per namespace, one after the other, as
+// kubectl only acts on multiple resources if these are in the same namespace.
+func forEachNamespace(items []navigator.Item, fn func(ns string, resources ...string) tea.Cmd) tea.Cmd {
+	return tea.Sequence(perNamespace(items, fn)...)
+}
+
+// perNamespace returns a command per namespace, with the resources within it.
+func perNamespace(items []navigator.Item, fn func(ns string, resources ...string) tea.Cmd) []tea.Cmd {
+	resources := map[string][]string{}
+	for _, item := range items {
+		trace, ok := item.Data.(*xplane.Resource)
+		if !ok {
+			continue
+		}
+		ns, _ := ds.GetPath[string](trace.Unstructured.Object, "metadata", "namespace")
+		resources[ns] = append(resources[ns], item.ID)
+	}
+
+	cmds := []tea.Cmd{}
+	for _, ns := range slices.Sorted(maps.Keys(resources)) {
+		cmds = append(cmds, fn(ns, resources[ns]...))
+	}
+	return cmds
 }
@@ -1,14 +1,27 @@
 package app
 
-import "github.com/charmbracelet/bubbles/key"
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/key"
+)
 
 type KeyMap struct {
 	Quit     key.Binding
 	FailQuit key.Binding
+	NextTab  key.Binding
+	PrevTab  key.Binding
+	CloseTab key.Binding
+	GoToTab  key.Binding
 }
 
 // DefaultKeyMap returns a default set of keybindings.
 func DefaultKeyMap() KeyMap {
+	goTo := []string{}
+	for i := 1; i <= 9; i++ {
+		goTo = append(goTo, fmt.Sprintf("alt+%d", i))
+	}
+
 	return KeyMap{
 		Quit: key.NewBinding(
 			key.WithKeys("ctrl+c"),
@@ -16,5 +29,21 @@ func DefaultKeyMap() KeyMap {
 		FailQuit: key.NewBinding(
 			key.WithKeys("q"),
 		),
+		NextTab: key.NewBinding(
+			key.WithKeys("t", "alt+right"),
+			key.WithHelp("t", "next tab"),
+		),
+		PrevTab: key.NewBinding(
+			key.WithKeys("T", "alt+left"),
+			key.WithHelp("T", "previous tab"),
+		),
+		CloseTab: key.NewBinding(
+			key.WithKeys("ctrl+w"),
+			key.WithHelp("ctrl+w", "close tab"),
+		),
+		GoToTab: key.NewBinding(
+			key.WithKeys(goTo...),
+			key.WithHelp("alt+[1-9]", "go to tab"),
+		),
 	}
 }
@@ -3,6 +3,7 @@ package app
 import (
 	"fmt"
 	"log/slog"
+	"time"
 
 	navigatorpane "github.com/brunoluiz/xpdig/internal/bubbles/layout/xpnavigator"
 	tea "github.com/charmbracelet/bubbletea"
@@ -17,17 +18,31 @@ const (
 )
 
 type kubectl interface {
-	Edit(ns, resource string) tea.Cmd
+	Edit(ns string, resources ...string) tea.Cmd
 	Describe(ns, resource string) tea.Cmd
 	Get(ns, resource string) tea.Cmd
-	Delete(ns, resource string) tea.Cmd
+	Delete(ns string, resources ...string) tea.Cmd
+	Annotate(ns string, annotations []string, resources ...string) tea.Cmd
+	SetContext(kubectx string)
 }
 
 type Model struct {
-	keyMap    KeyMap
-	navigator navigatorpane.Model
-	logger    *slog.Logger
-	kubectl   kubectl
+	keyMap  KeyMap
+	styles  Styles
+	glyphs  navigatorpane.Glyphs
+	logger  *slog.Logger
+	kubectl kubectl
+
+	tabs   []tab
+	active int
+	nextID int
+	newTab func(navigatorpane.Target) navigatorpane.Model
+
+	watch         bool
+	watchInterval time.Duration
+
+	width  int
+	height int
 
 	pane Pane
 	err  error
@@ -35,6 +50,46 @@ type Model struct {
 
 type WithOpt func(*Model)
 
+// WithKeyMap replaces the default keybindings.
+func WithKeyMap(km KeyMap) func(*Model) {
+	return func(m *Model) { m.keyMap = km }
+}
+
+// WithStyles replaces the default tab bar styles.
+func WithStyles(s Styles) func(*Model) {
+	return func(m *Model) { m.styles = s }
+}
+
+// WithGlyphs replaces the default health glyphs of the tab bar.
+func WithGlyphs(g navigatorpane.Glyphs) func(*Model) {
+	return func(m *Model) { m.glyphs = g }
+}
+
+// WithTabs opens more tabs, besides the one given to New.
+func WithTabs(navigatorModels ...navigatorpane.Model) func(*Model) {
+	return func(m *Model) {
+		for _, n := range navigatorModels {
+			m.addTab(n)
+		}
+	}
+}
+
+// WithNewTab allows opening tabs at runtime (eg: `:tabnew`), creating their
+// models through fn.
+func WithNewTab(fn func(navigatorpane.Target) navigatorpane.Model) func(*Model) {
+	return func(m *Model) { m.newTab = fn }
+}
+
+// WithWatch refreshes every tab once per interval, one after the other, so
+// tabs share a single polling loop. Tabs are expected to be created with
+// navigatorpane.WithExternalWatch.
+func WithWatch(enabled bool, interval time.Duration) func(*Model) {
+	return func(m *Model) {
+		m.watch = enabled
+		m.watchInterval = interval
+	}
+}
+
 func New(
 	logger *slog.Logger,
 	kubectl kubectl,
@@ -42,12 +97,15 @@ func New(
 	opts ...WithOpt,
 ) *Model {
 	m := &Model{
-		keyMap:    DefaultKeyMap(),
-		logger:    logger,
-		navigator: navigatorModel,
-		kubectl:   kubectl,
-		pane:      PaneNavigator,
+		keyMap:        DefaultKeyMap(),
+		styles:        DefaultStyles(),
+		glyphs:        navigatorpane.DefaultGlyphs(),
+		logger:        logger,
+		kubectl:       kubectl,
+		pane:          PaneNavigator,
+		watchInterval: 10 * time.Second,
 	}
+	m.addTab(navigatorModel)
 
 	for _, opt := range opts {
 		opt(m)
@@ -57,9 +115,14 @@ func New(
 }
 
 func (m Model) Init() tea.Cmd {
-	return tea.Batch(
-		m.navigator.Init(),
-	)
+	cmds := []tea.Cmd{}
+	for _, t := range m.tabs {
+		cmds = append(cmds, wrap(t.id, t.navigator.Init()))
+	}
+	if m.watch {
+		cmds = append(cmds, m.scheduleWatch())
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m Model) View() string {
@@ -67,10 +130,18 @@ func (m Model) View() string {
 	case PaneIrrecoverableError:
 		return fmt.Sprintf("There was a fatal error:\n%s\nPress q to exit", m.err.Error())
 	case PaneNavigator:
-		return lipgloss.JoinVertical(
-			lipgloss.Left,
-			m.navigator.View(),
-		)
+		t := m.tabs[m.active]
+		main := t.navigator.View()
+		if t.err != nil {
+			main = lipgloss.Place(m.width, m.height-1, lipgloss.Center, lipgloss.Center, fmt.Sprintf(
+				"There was an error tracing %s:\n%s\nPress %s to close the tab",
+				t.title(), t.err.Error(), m.keyMap.CloseTab.Help().Key,
+			))
+		}
+		if len(m.tabs) == 1 {
+			return main
+		}
+		return lipgloss.JoinVertical(lipgloss.Left, m.tabBar(), main)
 	default:
 		return "No pane selected"
 	}
@@ -0,0 +1,17 @@
+package app
+
+import "github.com/charmbracelet/lipgloss"
+
+type Styles struct {
+	Tab       lipgloss.Style
+	ActiveTab lipgloss.Style
+	Unhealthy lipgloss.Style
+}
+
+func DefaultStyles() Styles {
+	return Styles{
+		Tab:       lipgloss.NewStyle().Padding(0, 1).Foreground(lipgloss.Color("7")).Background(lipgloss.Color("8")),
+		ActiveTab: lipgloss.NewStyle().Padding(0, 1).Bold(true).Foreground(lipgloss.Color("7")).Background(lipgloss.Color("5")),
+		Unhealthy: lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
+	}
+}
@@ -0,0 +1,249 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/statusbar"
+	navigatorpane "github.com/brunoluiz/xpdig/internal/bubbles/layout/xpnavigator"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// ErrNewTabUnavailable is returned when opening tabs at runtime, without a
+// way of creating them (see WithNewTab).
+var ErrNewTabUnavailable = errors.New("opening tabs is not available")
+
+// tab is a trace being explored, with its own navigator.
+type tab struct {
+	id        int
+	navigator navigatorpane.Model
+	err       error
+}
+
+func (t tab) title() string {
+	if h := t.navigator.Health(); h.Loaded {
+		return h.Name
+	}
+	return t.navigator.Target().String()
+}
+
+// tabMsg is a message sent by the commands of a tab, so it is routed back to
+// it even if it is not the active one (eg: traces loaded in the background).
+type tabMsg struct {
+	id  int
+	msg tea.Msg
+}
+
+// wrap tags the messages sent by cmd with the tab ID.
+func wrap(id int, cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		switch msg := cmd().(type) {
+		case nil:
+			return nil
+		case tea.BatchMsg:
+			cmds := make(tea.BatchMsg, 0, len(msg))
+			for _, c := range msg {
+				cmds = append(cmds, wrap(id, c))
+			}
+			return cmds
+		default:
+			return tabMsg{id: id, msg: msg}
+		}
+	}
+}
+
+// isRuntimeMsg returns if a message is meant for the bubbletea runtime (eg:
+// sequences or executing processes), as these can not be tagged.
+func isRuntimeMsg(msg tea.Msg) bool {
+	t := reflect.TypeOf(msg)
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.PkgPath() == reflect.TypeOf(tea.BatchMsg{}).PkgPath()
+}
+
+// eventWatchTick refreshes every tab, when watching.
+type eventWatchTick struct{}
+
+func (m *Model) addTab(n navigatorpane.Model) int {
+	m.nextID++
+	m.tabs = append(m.tabs, tab{id: m.nextID, navigator: n})
+	return len(m.tabs) - 1
+}
+
+func (m Model) tabIndex(id int) int {
+	return slices.IndexFunc(m.tabs, func(t tab) bool { return t.id == id })
+}
+
+// updateTab sends a message to a tab, tagging its commands.
+func (m *Model) updateTab(i int, msg tea.Msg) tea.Cmd {
+	var cmd tea.Cmd
+	m.tabs[i].navigator, cmd = m.tabs[i].navigator.Update(msg)
+	return wrap(m.tabs[i].id, cmd)
+}
+
+// tabSize is the size of the tabs, which loses a line to the tab bar once
+// there is more than one tab.
+func (m Model) tabSize() tea.WindowSizeMsg {
+	if len(m.tabs) > 1 {
+		return tea.WindowSizeMsg{Width: m.width, Height: m.height - 1}
+	}
+	return tea.WindowSizeMsg{Width: m.width, Height: m.height}
+}
+
+func (m *Model) resizeTabs() tea.Cmd {
+	cmds := []tea.Cmd{}
+	for i := range m.tabs {
+		cmds = append(cmds, m.updateTab(i, m.tabSize()))
+	}
+	return tea.Batch(cmds...)
+}
+
+func (m *Model) onOpenTab(target navigatorpane.Target) tea.Cmd {
+	if m.newTab == nil {
+		return m.toast(ErrNewTabUnavailable)
+	}
+
+	single := len(m.tabs) == 1
+	m.active = m.addTab(m.newTab(target))
+	m.kubectl.SetContext(target.Context)
+
+	cmds := []tea.Cmd{wrap(m.tabs[m.active].id, m.tabs[m.active].navigator.Init())}
+	if single {
+		// The tab bar takes a line once there is more than one tab
+		cmds = append(cmds, m.resizeTabs())
+	} else {
+		cmds = append(cmds, m.updateTab(m.active, m.tabSize()))
+	}
+	return tea.Batch(cmds...)
+}
+
+// onCloseTab closes the active tab, quitting once there are no tabs left.
+func (m *Model) onCloseTab() tea.Cmd {
+	if len(m.tabs) == 1 {
+		return tea.Interrupt
+	}
+
+	m.tabs = slices.Delete(m.tabs, m.active, m.active+1)
+	m.active = min(m.active, len(m.tabs)-1)
+	m.kubectl.SetContext(m.tabs[m.active].navigator.Target().Context)
+	if len(m.tabs) == 1 {
+		return m.resizeTabs()
+	}
+	return nil
+}
+
+// onMouse sends mouse events to the active tab, relative to it as the tab bar
+// takes the first line once there is more than one tab. Clicking the tab bar
+// switches tabs.
+func (m Model) onMouse(msg tea.MouseMsg) (tea.Model, tea.Cmd) {
+	if len(m.tabs) == 1 {
+		return m.onTabMsg(m.active, msg)
+	}
+	if msg.Y > 0 {
+		msg.Y--
+		return m.onTabMsg(m.active, msg)
+	}
+
+	if msg.Action == tea.MouseActionPress && msg.Button == tea.MouseButtonLeft {
+		m.switchTab(m.tabAt(msg.X))
+	}
+	return m, nil
+}
+
+// tabAt returns the index of the tab shown at column x of the tab bar, or -1.
+func (m Model) tabAt(x int) int {
+	offset := 0
+	for i, t := range m.renderTabs() {
+		offset += lipgloss.Width(t)
+		if x < offset {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Model) switchTab(i int) {
+	if i < 0 || i >= len(m.tabs) || i == m.active {
+		return
+	}
+	m.active = i
+	m.kubectl.SetContext(m.tabs[i].navigator.Target().Context)
+}
+
+// onTabError shows the error within its tab, unless it is the only one.
+func (m *Model) onTabError(i int, err error) {
+	if len(m.tabs) == 1 {
+		m.setIrrecoverableError(err)
+		return
+	}
+	m.tabs[i].err = err
+	m.logger.Error("tab error", "tab", m.tabs[i].title(), "error", err)
+}
+
+func (m Model) toast(err error) tea.Cmd {
+	return func() tea.Msg { return statusbar.EventToast{Err: err} }
+}
+
+// scheduleWatch waits for the interval, before refreshing the tabs.
+func (m Model) scheduleWatch() tea.Cmd {
+	return tea.Tick(m.watchInterval, func(time.Time) tea.Msg { return eventWatchTick{} })
+}
+
+// onWatch refreshes the tabs one after the other, so only one trace is loaded
+// at a time, scheduling the next refresh once all are done.
+func (m Model) onWatch() tea.Cmd {
+	cmds := []tea.Cmd{}
+	for _, t := range m.tabs {
+		if t.err == nil {
+			cmds = append(cmds, wrap(t.id, t.navigator.Refresh()))
+		}
+	}
+	return tea.Sequence(append(cmds, m.scheduleWatch())...)
+}
+
+// tabBar shows every tab with its aggregate health.
+func (m Model) tabBar() string {
+	return ansi.Truncate(lipgloss.JoinHorizontal(lipgloss.Top, m.renderTabs()...), m.width, "…")
+}
+
+// renderTabs returns each tab as shown in the tab bar, in order.
+func (m Model) renderTabs() []string {
+	tabs := []string{}
+	for i, t := range m.tabs {
+		style := m.styles.Tab
+		if i == m.active {
+			style = m.styles.ActiveTab
+		}
+		glyphStyle := style.UnsetPadding()
+
+		h := t.navigator.Health()
+		glyph, label := "…", t.title()
+		switch {
+		case t.err != nil:
+			glyph, glyphStyle = m.glyphs.Unhealthy, m.styles.Unhealthy.Inherit(glyphStyle)
+			label += " (error)"
+		case h.Unhealthy > 0:
+			glyph, glyphStyle = m.glyphs.Unhealthy, m.styles.Unhealthy.Inherit(glyphStyle)
+			label += fmt.Sprintf(" (%d/%d)", h.Unhealthy, h.Resources)
+		case h.Loaded:
+			glyph = m.glyphs.Healthy
+		}
+
+		tabs = append(tabs, style.Render(strings.Join([]string{
+			style.UnsetPadding().Render(fmt.Sprintf("%d ", i+1)),
+			glyphStyle.Render(glyph),
+			style.UnsetPadding().Render(" " + label),
+		}, "")))
+	}
+	return tabs
+}
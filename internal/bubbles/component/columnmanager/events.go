@@ -0,0 +1,90 @@
+package columnmanager
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// EventColumnsUpdated is sent whenever columns are shown, hidden, moved or resized.
+type EventColumnsUpdated struct {
+	Columns []Column
+}
+
+// EventLayoutToggled is sent when switching between short and wide layouts.
+type EventLayoutToggled struct{}
+
+// EventColumnsReset is sent when going back to the default columns.
+type EventColumnsReset struct{}
+
+type EventClosed struct{}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	case tea.KeyMsg:
+		cmd = m.onKey(msg)
+	}
+	return m, cmd
+}
+
+func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
+	if len(m.columns) == 0 {
+		return nil
+	}
+
+	c := &m.columns[m.cursor]
+	switch {
+	case key.Matches(msg, m.KeyMap.Up):
+		m.cursor = max(m.cursor-1, 0)
+	case key.Matches(msg, m.KeyMap.Down):
+		m.cursor = min(m.cursor+1, len(m.columns)-1)
+	case key.Matches(msg, m.KeyMap.Toggle):
+		if c.Fixed {
+			return nil
+		}
+		c.Hidden = !c.Hidden
+		return m.updated()
+	case key.Matches(msg, m.KeyMap.MoveUp):
+		if !m.swap(m.cursor, m.cursor-1) {
+			return nil
+		}
+		m.cursor--
+		return m.updated()
+	case key.Matches(msg, m.KeyMap.MoveDown):
+		if !m.swap(m.cursor, m.cursor+1) {
+			return nil
+		}
+		m.cursor++
+		return m.updated()
+	case key.Matches(msg, m.KeyMap.Narrower):
+		c.Width = max(c.Width-widthStep, minWidth)
+		return m.updated()
+	case key.Matches(msg, m.KeyMap.Wider):
+		c.Width += widthStep
+		return m.updated()
+	case key.Matches(msg, m.KeyMap.ToggleLayout):
+		return func() tea.Msg { return EventLayoutToggled{} }
+	case key.Matches(msg, m.KeyMap.Reset):
+		return func() tea.Msg { return EventColumnsReset{} }
+	case key.Matches(msg, m.KeyMap.Close):
+		return func() tea.Msg { return EventClosed{} }
+	}
+	return nil
+}
+
+// swap moves columns around, as long as fixed columns stay in place.
+func (m *Model) swap(i, j int) bool {
+	if j < 0 || j >= len(m.columns) || m.columns[i].Fixed || m.columns[j].Fixed {
+		return false
+	}
+	m.columns[i], m.columns[j] = m.columns[j], m.columns[i]
+	return true
+}
+
+func (m Model) updated() tea.Cmd {
+	cols := m.Columns()
+	return func() tea.Msg { return EventColumnsUpdated{Columns: cols} }
+}
@@ -0,0 +1,62 @@
+package columnmanager
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Up           key.Binding
+	Down         key.Binding
+	Toggle       key.Binding
+	MoveUp       key.Binding
+	MoveDown     key.Binding
+	Narrower     key.Binding
+	Wider        key.Binding
+	ToggleLayout key.Binding
+	Reset        key.Binding
+	Close        key.Binding
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Toggle: key.NewBinding(
+			key.WithKeys(" ", "space"),
+			key.WithHelp("space", "show/hide"),
+		),
+		MoveUp: key.NewBinding(
+			key.WithKeys("shift+up", "K"),
+			key.WithHelp("K", "move up"),
+		),
+		MoveDown: key.NewBinding(
+			key.WithKeys("shift+down", "J"),
+			key.WithHelp("J", "move down"),
+		),
+		Narrower: key.NewBinding(
+			key.WithKeys("left", "h"),
+			key.WithHelp("←/h", "narrower"),
+		),
+		Wider: key.NewBinding(
+			key.WithKeys("right", "l"),
+			key.WithHelp("→/l", "wider"),
+		),
+		ToggleLayout: key.NewBinding(
+			key.WithKeys("w"),
+			key.WithHelp("w", "short/wide"),
+		),
+		Reset: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "reset"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "enter", "C"),
+			key.WithHelp("esc", "close"),
+		),
+	}
+}
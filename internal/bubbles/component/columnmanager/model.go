@@ -0,0 +1,98 @@
+// Package columnmanager is a modal to show, hide, reorder and resize columns.
+package columnmanager
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	minWidth  = 4
+	widthStep = 2
+)
+
+// Column is a column being managed. Fixed columns (eg: the tree column)
+// can be resized, but not hidden or moved.
+type Column struct {
+	Title  string
+	Width  int
+	Hidden bool
+	Fixed  bool
+}
+
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Help   help.Model
+
+	title   string
+	columns []Column
+	cursor  int
+	width   int
+	height  int
+}
+
+func New() Model {
+	return Model{
+		KeyMap: DefaultKeyMap(),
+		Styles: DefaultStyles(),
+		Help:   help.New(),
+	}
+}
+
+func (m Model) Init() tea.Cmd { return nil }
+
+// SetColumns sets which columns are managed, with title describing their layout.
+func (m *Model) SetColumns(title string, cols []Column) {
+	m.title = title
+	m.columns = cols
+	m.cursor = max(min(m.cursor, len(cols)-1), 0)
+}
+
+func (m Model) Columns() []Column {
+	return append([]Column{}, m.columns...)
+}
+
+func (m Model) View() string {
+	titleWidth := 0
+	for _, c := range m.columns {
+		titleWidth = max(titleWidth, lipgloss.Width(c.Title))
+	}
+
+	lines := []string{m.Styles.Title.Render(m.title), ""}
+	for i, c := range m.columns {
+		check := "[x]"
+		if c.Hidden {
+			check = "[ ]"
+		}
+		line := fmt.Sprintf(" %s %-*s %4d ", check, titleWidth, c.Title, c.Width)
+		switch {
+		case i == m.cursor:
+			line = m.Styles.Selected.Render(line)
+		case c.Hidden:
+			line = m.Styles.Hidden.Render(line)
+		}
+		lines = append(lines, line)
+	}
+	lines = append(lines, "", m.Styles.Help.Render(m.Help.ShortHelpView(m.ShortHelp())))
+
+	return lipgloss.Place(
+		m.width, m.height,
+		lipgloss.Center, lipgloss.Center,
+		m.Styles.Box.Render(strings.Join(lines, "\n")),
+	)
+}
+
+func (m Model) ShortHelp() []key.Binding {
+	k := m.KeyMap
+	return []key.Binding{k.Toggle, k.MoveUp, k.MoveDown, k.Narrower, k.Wider, k.ToggleLayout, k.Reset, k.Close}
+}
+
+func (m Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
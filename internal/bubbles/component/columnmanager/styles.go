@@ -0,0 +1,21 @@
+package columnmanager
+
+import "github.com/charmbracelet/lipgloss"
+
+type Styles struct {
+	Box      lipgloss.Style
+	Title    lipgloss.Style
+	Selected lipgloss.Style
+	Hidden   lipgloss.Style
+	Help     lipgloss.Style
+}
+
+func DefaultStyles() Styles {
+	return Styles{
+		Box:      lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1),
+		Title:    lipgloss.NewStyle().Bold(true),
+		Selected: lipgloss.NewStyle().Foreground(lipgloss.Color("0")).Background(lipgloss.Color("7")),
+		Hidden:   lipgloss.NewStyle().Foreground(lipgloss.Color("8")),
+		Help:     lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#333", Dark: "#eee"}),
+	}
+}
@@ -0,0 +1,73 @@
+package confirm
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// EventClosed is sent when the modal is closed, either confirmed or not.
+type EventClosed struct{}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.layout()
+	case tea.KeyMsg:
+		cmd = m.onKey(msg)
+	}
+	return m, cmd
+}
+
+func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
+	if m.request.Input != "" {
+		return m.onInputKey(msg)
+	}
+
+	switch {
+	case key.Matches(msg, m.KeyMap.Up):
+		m.viewport.ScrollUp(1)
+	case key.Matches(msg, m.KeyMap.Down):
+		m.viewport.ScrollDown(1)
+	case key.Matches(msg, m.KeyMap.Confirm):
+		return tea.Batch(closed, m.confirmed())
+	case key.Matches(msg, m.KeyMap.Cancel), key.Matches(msg, m.KeyMap.Deny):
+		return closed
+	}
+	return nil
+}
+
+// onInputKey handles keys while asking for an input, where only non-printable
+// keys are bindings, so they do not get in the way of typing.
+func (m *Model) onInputKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case msg.Type == tea.KeyUp:
+		m.viewport.ScrollUp(1)
+	case msg.Type == tea.KeyDown:
+		m.viewport.ScrollDown(1)
+	case key.Matches(msg, m.KeyMap.Cancel):
+		return closed
+	case key.Matches(msg, m.KeyMap.Submit):
+		if m.input.Value() == "" {
+			return nil
+		}
+		return tea.Batch(closed, m.confirmed())
+	default:
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		return cmd
+	}
+	return nil
+}
+
+func closed() tea.Msg { return EventClosed{} }
+
+func (m Model) confirmed() tea.Cmd {
+	if m.request.OnConfirm == nil {
+		return nil
+	}
+	onConfirm, input := m.request.OnConfirm, m.input.Value()
+	return func() tea.Msg { return onConfirm(input) }
+}
@@ -0,0 +1,118 @@
+// Package confirm is a modal asking to confirm an action over a list of
+// objects, optionally asking for an input (eg: annotations) as well.
+package confirm
+
+import (
+	"strings"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/modal"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Request describes what is being confirmed.
+type Request struct {
+	Title string
+	Items []string
+	// Input is the placeholder of the input asked together with the
+	// confirmation, with no input being asked if empty.
+	Input string
+	// OnConfirm returns the message sent once confirmed, receiving the input.
+	OnConfirm func(input string) tea.Msg
+}
+
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Help   help.Model
+
+	request  Request
+	input    textinput.Model
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+func New() Model {
+	input := textinput.New()
+	input.Prompt = "> "
+
+	return Model{
+		KeyMap:   DefaultKeyMap(),
+		Styles:   DefaultStyles(),
+		Help:     help.New(),
+		input:    input,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (m Model) Init() tea.Cmd { return nil }
+
+// Ask replaces the request being confirmed.
+func (m *Model) Ask(r Request) tea.Cmd {
+	m.request = r
+	m.input.Reset()
+	m.input.Placeholder = r.Input
+	m.layout()
+	m.viewport.GotoTop()
+
+	if r.Input == "" {
+		m.input.Blur()
+		return nil
+	}
+	return m.input.Focus()
+}
+
+func (m Model) frame() modal.Frame {
+	return modal.Frame{Box: m.Styles.Box, Margin: modal.Margin, Width: m.width, Height: m.height}
+}
+
+func (m *Model) layout() {
+	frame := m.frame()
+	width := frame.ContentWidth()
+	lines := make([]string, 0, len(m.request.Items))
+	for _, item := range m.request.Items {
+		lines = append(lines, ansi.Truncate("- "+item, width, "…"))
+	}
+
+	// Title, help, input and the blank lines around the list
+	chrome := 4
+	if m.request.Input != "" {
+		chrome += 2
+	}
+	m.input.Width = max(width-lipgloss.Width(m.input.Prompt)-1, 1)
+	m.viewport.Width = width
+	m.viewport.Height = frame.ContentHeight(len(lines), chrome)
+	m.viewport.SetContent(strings.Join(lines, "\n"))
+}
+
+func (m Model) View() string {
+	components := []string{
+		m.Styles.Title.Render(m.request.Title),
+		"",
+		m.viewport.View(),
+	}
+	if m.request.Input != "" {
+		components = append(components, "", m.input.View())
+	}
+	components = append(components, "", m.Styles.Help.Render(m.Help.ShortHelpView(m.ShortHelp())))
+
+	return m.frame().View(components...)
+}
+
+func (m Model) ShortHelp() []key.Binding {
+	k := m.KeyMap
+	if m.request.Input != "" {
+		return []key.Binding{k.Up, k.Down, k.Submit, k.Cancel}
+	}
+	return []key.Binding{k.Up, k.Down, k.Confirm, k.Deny}
+}
+
+func (m Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
@@ -0,0 +1,17 @@
+package confirm
+
+import "github.com/charmbracelet/lipgloss"
+
+type Styles struct {
+	Box   lipgloss.Style
+	Title lipgloss.Style
+	Help  lipgloss.Style
+}
+
+func DefaultStyles() Styles {
+	return Styles{
+		Box:   lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1),
+		Title: lipgloss.NewStyle().Bold(true),
+		Help:  lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#333", Dark: "#eee"}),
+	}
+}
@@ -0,0 +1,65 @@
+package keyhelp
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// EventClosed is sent when the modal is closed.
+type EventClosed struct{}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.layout()
+	case tea.KeyMsg:
+		cmd = m.onKey(msg)
+	}
+	return m, cmd
+}
+
+func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
+	if m.input.Focused() {
+		return m.onInputKey(msg)
+	}
+
+	switch {
+	case key.Matches(msg, m.KeyMap.Up):
+		m.viewport.ScrollUp(1)
+	case key.Matches(msg, m.KeyMap.Down):
+		m.viewport.ScrollDown(1)
+	case key.Matches(msg, m.KeyMap.PageUp):
+		m.viewport.PageUp()
+	case key.Matches(msg, m.KeyMap.PageDown):
+		m.viewport.PageDown()
+	case key.Matches(msg, m.KeyMap.Search):
+		m.layout()
+		return m.input.Focus()
+	case key.Matches(msg, m.KeyMap.Close):
+		return func() tea.Msg { return EventClosed{} }
+	}
+	return nil
+}
+
+// onInputKey filters the bindings while typing, with the search being kept
+// once confirmed and cleared once quit.
+func (m *Model) onInputKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.KeyMap.SearchConfirm):
+		m.input.Blur()
+	case key.Matches(msg, m.KeyMap.SearchQuit):
+		m.input.Blur()
+		m.input.Reset()
+	default:
+		var cmd tea.Cmd
+		m.input, cmd = m.input.Update(msg)
+		m.layout()
+		m.viewport.GotoTop()
+		return cmd
+	}
+	m.layout()
+	return nil
+}
@@ -0,0 +1,52 @@
+package keyhelp
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Up            key.Binding
+	Down          key.Binding
+	PageUp        key.Binding
+	PageDown      key.Binding
+	Search        key.Binding
+	SearchConfirm key.Binding
+	SearchQuit    key.Binding
+	Close         key.Binding
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		PageUp: key.NewBinding(
+			key.WithKeys("pgup", "ctrl+b"),
+			key.WithHelp("pgup", "page up"),
+		),
+		PageDown: key.NewBinding(
+			key.WithKeys("pgdown", "ctrl+f"),
+			key.WithHelp("pgdown", "page down"),
+		),
+		Search: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "search"),
+		),
+		SearchConfirm: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "search confirm"),
+		),
+		SearchQuit: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "search quit"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "?", "q"),
+			key.WithHelp("esc/?", "close"),
+		),
+	}
+}
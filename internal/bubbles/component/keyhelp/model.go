@@ -0,0 +1,194 @@
+// Package keyhelp is a modal listing key bindings in columns, grouped by what
+// they do, which can be searched.
+package keyhelp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/modal"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+const (
+	// margin is kept around the modal, smaller than other modals' as it
+	// needs the room for its columns.
+	margin = 2
+	// gap is kept between columns.
+	gap = 4
+)
+
+// Group is a titled set of bindings, eg: movement or search.
+type Group struct {
+	Title    string
+	Bindings []key.Binding
+}
+
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Help   help.Model
+
+	groups   []Group
+	input    textinput.Model
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+func New() Model {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Placeholder = "Search key bindings..."
+
+	return Model{
+		KeyMap:   DefaultKeyMap(),
+		Styles:   DefaultStyles(),
+		Help:     help.New(),
+		input:    input,
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (m Model) Init() tea.Cmd { return nil }
+
+// SetGroups replaces the bindings shown, clearing any search.
+func (m *Model) SetGroups(groups []Group) {
+	m.groups = groups
+	m.input.Blur()
+	m.input.Reset()
+	m.layout()
+	m.viewport.GotoTop()
+}
+
+// filtered returns the enabled bindings with help, matching the search
+// query by keys, description or group title.
+func (m Model) filtered() []Group {
+	query := strings.ToLower(m.input.Value())
+	groups := []Group{}
+	for _, g := range m.groups {
+		titleMatches := strings.Contains(strings.ToLower(g.Title), query)
+		bindings := []key.Binding{}
+		for _, b := range g.Bindings {
+			h := b.Help()
+			if !b.Enabled() || h.Key == "" {
+				continue
+			}
+			if titleMatches || strings.Contains(strings.ToLower(h.Key+" "+h.Desc), query) {
+				bindings = append(bindings, b)
+			}
+		}
+		if len(bindings) > 0 {
+			groups = append(groups, Group{Title: g.Title, Bindings: bindings})
+		}
+	}
+	return groups
+}
+
+func (m Model) renderGroup(g Group) string {
+	keyWidth := 0
+	for _, b := range g.Bindings {
+		keyWidth = max(keyWidth, lipgloss.Width(b.Help().Key))
+	}
+
+	lines := []string{m.Styles.Group.Render(g.Title)}
+	for _, b := range g.Bindings {
+		lines = append(lines, lipgloss.JoinHorizontal(lipgloss.Top,
+			m.Styles.Key.Width(keyWidth+2).Render(b.Help().Key),
+			m.Styles.Desc.Render(b.Help().Desc),
+		))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// columns lays the groups out in as many columns as the width fits, keeping
+// their order and balancing the columns height.
+func (m Model) columns(groups []Group, width int) string {
+	blocks := make([]string, 0, len(groups))
+	blockWidth, total := 0, 0
+	for _, g := range groups {
+		block := m.renderGroup(g)
+		blocks = append(blocks, block)
+		blockWidth = max(blockWidth, lipgloss.Width(block))
+		total += lipgloss.Height(block) + 1
+	}
+
+	count := max(min(width/(blockWidth+gap), len(blocks)), 1)
+	target := (total + count - 1) / count
+
+	columns := []string{}
+	current, height := []string{}, 0
+	for _, block := range blocks {
+		if height > 0 && height+lipgloss.Height(block) > target && len(columns) < count-1 {
+			columns = append(columns, lipgloss.JoinVertical(lipgloss.Left, current...))
+			current, height = []string{}, 0
+		}
+		current = append(current, block, "")
+		height += lipgloss.Height(block) + 1
+	}
+	columns = append(columns, lipgloss.JoinVertical(lipgloss.Left, current...))
+
+	for i := range columns[:len(columns)-1] {
+		columns[i] = lipgloss.NewStyle().Width(blockWidth + gap).Render(columns[i])
+	}
+	return strings.TrimRight(lipgloss.JoinHorizontal(lipgloss.Top, columns...), "\n ")
+}
+
+func (m Model) frame() modal.Frame {
+	return modal.Frame{Box: m.Styles.Box, Margin: margin, Width: m.width, Height: m.height}
+}
+
+func (m *Model) layout() {
+	frame := m.frame()
+	width := frame.ContentWidth()
+
+	content := fmt.Sprintf("No key bindings match '%s'", m.input.Value())
+	if groups := m.filtered(); len(groups) > 0 {
+		content = m.columns(groups, width)
+	}
+
+	// Title, search, help and the blank lines around the bindings
+	chrome := 4
+	if m.searching() {
+		chrome += 2
+	}
+	m.input.Width = max(width-lipgloss.Width(m.input.Prompt)-1, 1)
+	m.viewport.Width = width
+	m.viewport.Height = frame.ContentHeight(lipgloss.Height(content), chrome)
+	m.viewport.SetContent(content)
+}
+
+func (m Model) searching() bool {
+	return m.input.Focused() || m.input.Value() != ""
+}
+
+func (m Model) View() string {
+	components := []string{m.Styles.Title.Render("Key bindings")}
+	if m.searching() {
+		components = append(components, "", m.input.View())
+	}
+	components = append(components,
+		"",
+		m.viewport.View(),
+		"",
+		m.Styles.Help.Render(m.Help.ShortHelpView(m.ShortHelp())),
+	)
+	return m.frame().View(components...)
+}
+
+func (m Model) ShortHelp() []key.Binding {
+	k := m.KeyMap
+	if m.input.Focused() {
+		return []key.Binding{k.SearchConfirm, k.SearchQuit}
+	}
+	return []key.Binding{k.Up, k.Down, k.Search, k.Close}
+}
+
+func (m Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
@@ -0,0 +1,23 @@
+package keyhelp
+
+import "github.com/charmbracelet/lipgloss"
+
+type Styles struct {
+	Box   lipgloss.Style
+	Title lipgloss.Style
+	Group lipgloss.Style
+	Key   lipgloss.Style
+	Desc  lipgloss.Style
+	Help  lipgloss.Style
+}
+
+func DefaultStyles() Styles {
+	return Styles{
+		Box:   lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).Padding(0, 1),
+		Title: lipgloss.NewStyle().Bold(true),
+		Group: lipgloss.NewStyle().Bold(true).Underline(true),
+		Key:   lipgloss.NewStyle().Bold(true),
+		Desc:  lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#333", Dark: "#eee"}),
+		Help:  lipgloss.NewStyle().Foreground(lipgloss.AdaptiveColor{Light: "#333", Dark: "#eee"}),
+	}
+}
@@ -0,0 +1,34 @@
+// Package modal is the frame shared by the modals shown over the trace: a box
+// centered in the terminal, with some margin around it.
+package modal
+
+import "github.com/charmbracelet/lipgloss"
+
+// Margin is kept around modals, so what is behind them is still noticeable.
+const Margin = 4
+
+// Frame is the box of a modal, shown in a terminal of the given size.
+type Frame struct {
+	Box    lipgloss.Style
+	Margin int
+	Width  int
+	Height int
+}
+
+// ContentWidth returns the width left for the content within the box.
+func (f Frame) ContentWidth() int {
+	return max(f.Width-2*f.Margin-f.Box.GetHorizontalFrameSize(), 1)
+}
+
+// ContentHeight returns how much of the content height fits within the box,
+// next to the chrome lines (eg: title, help and blank lines around it).
+func (f Frame) ContentHeight(height, chrome int) int {
+	return max(min(height, f.Height-2*f.Margin-f.Box.GetVerticalFrameSize()-chrome), 1)
+}
+
+// View renders the components one below the other within the box, placed in
+// the middle of the terminal.
+func (f Frame) View(components ...string) string {
+	box := f.Box.Render(lipgloss.JoinVertical(lipgloss.Left, components...))
+	return lipgloss.Place(f.Width, f.Height, lipgloss.Center, lipgloss.Center, box)
+}
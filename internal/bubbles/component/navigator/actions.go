@@ -0,0 +1,121 @@
+package navigator
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Action is done against the current (or selected) items, either through its
+// key or from elsewhere (eg: the command palette).
+type Action struct {
+	Name string
+	Help string
+	// Writes is set for actions changing resources
+	Writes  bool
+	Binding key.Binding
+
+	run func(m *Model) tea.Cmd
+}
+
+// Actions returns the actions available against items.
+func (m Model) Actions() []Action {
+	return []Action{
+		{
+			Name:    "describe",
+			Help:    "describe the resource",
+			Binding: m.KeyMap.Describe,
+			run: func(m *Model) tea.Cmd {
+				return func() tea.Msg { return EventItemDescribe{ID: m.Current().ID, Data: m.Current().Data} }
+			},
+		},
+		{
+			Name:    "get",
+			Help:    "show the resource as YAML",
+			Binding: m.KeyMap.Get,
+			run: func(m *Model) tea.Cmd {
+				return func() tea.Msg { return EventItemGet{ID: m.Current().ID, Data: m.Current().Data} }
+			},
+		},
+		{
+			Name:    "copy",
+			Help:    "copy the resource names",
+			Binding: m.KeyMap.Copy,
+			run: func(m *Model) tea.Cmd {
+				return func() tea.Msg {
+					return EventItemCopied{ID: m.Current().ID, Data: m.Current().Data, Items: m.items()}
+				}
+			},
+		},
+		{
+			Name:    "edit",
+			Help:    "edit the resources",
+			Writes:  true,
+			Binding: m.KeyMap.Edit,
+			run: func(m *Model) tea.Cmd {
+				items := m.items()
+				return confirmed("Edit", items, EventItemEdit{ID: m.Current().ID, Data: m.Current().Data, Items: items})
+			},
+		},
+		{
+			Name:    "delete",
+			Help:    "delete the resources",
+			Writes:  true,
+			Binding: m.KeyMap.Delete,
+			run: func(m *Model) tea.Cmd {
+				items := m.items()
+				return confirmed("Delete", items, EventItemDelete{ID: m.Current().ID, Data: m.Current().Data, Items: items})
+			},
+		},
+		{
+			Name:    "annotate",
+			Help:    "annotate the resources",
+			Writes:  true,
+			Binding: m.KeyMap.Annotate,
+			run:     (*Model).onAnnotate,
+		},
+		{
+			Name:    "pause",
+			Help:    "pause (or resume) the reconciliation of the resources",
+			Writes:  true,
+			Binding: m.KeyMap.Pause,
+			run:     func(m *Model) tea.Cmd { return m.onPause(m.items()) },
+		},
+		{
+			Name:    "pause-subtree",
+			Help:    "pause (or resume) the reconciliation of the resource and its descendants",
+			Writes:  true,
+			Binding: m.KeyMap.PauseSubtree,
+			run:     func(m *Model) tea.Cmd { return m.onPause(m.subtreeItems()) },
+		},
+		{
+			Name:    "reconcile",
+			Help:    "reconcile the resources now",
+			Writes:  true,
+			Binding: m.KeyMap.Reconcile,
+			run: func(m *Model) tea.Cmd {
+				items := m.items()
+				return confirmed("Reconcile", items, EventItemReconcile{Items: items})
+			},
+		},
+	}
+}
+
+// RunAction runs an action by its name, as if its key was pressed.
+func (m *Model) RunAction(name string) tea.Cmd {
+	for _, a := range m.Actions() {
+		if a.Name == name {
+			return a.run(m)
+		}
+	}
+	return nil
+}
+
+// onAction runs the action bound to the key, if any.
+func (m *Model) onAction(msg tea.KeyMsg) (tea.Cmd, bool) {
+	for _, a := range m.Actions() {
+		if key.Matches(msg, a.Binding) {
+			return a.run(m), true
+		}
+	}
+	return nil, false
+}
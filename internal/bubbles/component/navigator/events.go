@@ -1,7 +1,7 @@
 package navigator
 
 import (
-	"strings"
+	"fmt"
 
 	"github.com/brunoluiz/xpdig/internal/bubbles/component/table"
 	"github.com/charmbracelet/bubbles/key"
@@ -10,8 +10,9 @@ import (
 )
 
 type EventItemCopied struct {
-	ID   string
-	Data any
+	ID    string
+	Data  any
+	Items []Item
 }
 
 type EventItemDescribe struct {
@@ -29,25 +30,85 @@ type EventItemGet struct {
 	Data any
 }
 
+// EventItemEdit is sent to edit the selected items, with ID and Data being
+// the focused one.
 type EventItemEdit struct {
-	ID   string
-	Data any
+	ID    string
+	Data  any
+	Items []Item
 }
 
+// EventItemDelete is sent to delete the selected items, with ID and Data
+// being the focused one.
 type EventItemDelete struct {
-	ID   string
-	Data any
+	ID    string
+	Data  any
+	Items []Item
+}
+
+// EventItemAnnotate is sent to annotate items, with annotations being in the
+// `kubectl annotate` format (`key=value` or `key-` to remove one).
+type EventItemAnnotate struct {
+	Items       []Item
+	Annotations []string
+}
+
+// EventItemPause is sent to pause the reconciliation of items or, if Paused
+// is false, to resume it.
+type EventItemPause struct {
+	Items  []Item
+	Paused bool
+}
+
+// EventItemReconcile is sent to get items reconciled straight away.
+type EventItemReconcile struct {
+	Items []Item
+}
+
+// EventSelectionChanged is sent when rows get selected or unselected.
+type EventSelectionChanged struct {
+	Count int
+}
+
+// EventConfirm asks for a confirmation before acting on items, optionally
+// asking for an input as well (eg: annotations).
+type EventConfirm struct {
+	Title     string
+	Items     []Item
+	Input     string
+	OnConfirm func(input string) tea.Msg
 }
 
 type EventQuitted struct{}
 
+// EventShowFullHelp is sent when every key binding is meant to be shown.
+type EventShowFullHelp struct{}
+
+// EventZoomed is sent when the view is re-rooted on a subtree, with an empty
+// ID meaning that it is not zoomed anymore.
+type EventZoomed struct {
+	ID string
+}
+
+// EventOnlyUnhealthyToggled is sent when only unhealthy rows start or stop being shown.
+type EventOnlyUnhealthyToggled struct {
+	Enabled bool
+}
+
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	var cmd tea.Cmd
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		cmd = m.onResize(msg)
 	case tea.KeyMsg:
+		if m.searchMode != searchModeInput {
+			if navCmd, ok := m.onNavKey(msg); ok {
+				return m, navCmd
+			}
+		}
 		cmd = m.onKey(msg)
+	case tea.MouseMsg:
+		cmd = m.onMouse(msg)
 	case table.EventCursorUpdated:
 		cmd = m.onCursorUpdated(msg)
 	}
@@ -56,6 +117,9 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	case searchModeInput:
 		var searchCmd tea.Cmd
 		m.searchInput, searchCmd = m.searchInput.Update(msg)
+		if m.searchMode == searchModeInput {
+			_, m.searchErr = m.compileSearch()
+		}
 		return m, searchCmd
 	case searchModeInit:
 		m.searchMode = searchModeInput
@@ -71,7 +135,7 @@ func (m *Model) onResize(msg tea.WindowSizeMsg) tea.Cmd {
 	m.setSize(msg.Width, msg.Height)
 	m.table.SetWidth(msg.Width)
 	m.table.SetHeight(msg.Height)
-	m.SetColumns(m.table.Columns())
+	m.SetColumns(m.columns)
 	return nil
 }
 
@@ -86,34 +150,59 @@ func (m *Model) onCursorUpdated(msg table.EventCursorUpdated) tea.Cmd {
 func (m *Model) onSearch(msg tea.KeyMsg) tea.Cmd {
 	switch {
 	case key.Matches(msg, m.KeyMap.SearchConfirm):
+		search, err := m.compileSearch()
+		if err != nil {
+			m.searchErr = err
+			return nil
+		}
+		m.search = search
 		m.searchResult = m.searchInput.Value()
 		m.searchInput.Blur()
 		m.searchMode = searchModeFilter
+		if m.filtering {
+			m.refresh(m.Current().ID)
+		}
 		m.doSearch()
 		m.doLoadTable()
+	case key.Matches(msg, m.KeyMap.SearchMode):
+		m.setMatchMode(m.matchMode.next())
+		_, m.searchErr = m.compileSearch()
 	case key.Matches(msg, m.KeyMap.SearchQuit):
 		m.searchInput.Blur()
 		m.searchMode = searchModeOff
 		m.searchResult = ""
 		m.searchInput.Reset()
+		m.searchErr = nil
+		m.filtering = false
 	}
 	return nil
 }
 
-func (m *Model) doSearch() {
-	searchTerm := strings.ToLower(m.searchInput.Value())
-	m.cursorBySearchCursor = map[int]int{}
-	m.searchCursorByCursor = map[int]int{}
+// SetFilter starts filtering rows by the given query, as if it was searched
+// and then filtered by the user.
+func (m *Model) SetFilter(query string) error {
+	if query == "" {
+		m.onSearchQuit()
+		return nil
+	}
 
-	match := 0
-	for pos, v := range m.data {
-		if strings.Contains(strings.ToLower(v.ID), searchTerm) {
-			m.cursorBySearchCursor[match] = pos
-			m.searchCursorByCursor[pos] = match
-			match++
-		}
+	m.searchInput.SetValue(query)
+	search, err := m.compileSearch()
+	if err != nil {
+		return err
 	}
 
+	m.search = search
+	m.searchResult = query
+	m.searchMode = searchModeFilter
+	m.filtering = true
+	m.refresh(m.Current().ID)
+	return nil
+}
+
+func (m *Model) doSearch() {
+	m.indexSearch()
+
 	if len(m.cursorBySearchCursor) > 0 {
 		m.searchCursor = 0
 		m.cursor = m.cursorBySearchCursor[0]
@@ -121,11 +210,50 @@ func (m *Model) doSearch() {
 	}
 }
 
-func (m *Model) onSearchInit() {
+// indexSearch maps search results to their visible positions and vice-versa.
+func (m *Model) indexSearch() {
+	m.cursorBySearchCursor = map[int]int{}
+	m.searchCursorByCursor = map[int]int{}
+
+	positions := []int{}
+	for pos, i := range m.visible {
+		if m.matches(m.data[i]) {
+			positions = append(positions, pos)
+		}
+	}
+
+	for match, pos := range m.rankMatches(positions) {
+		m.cursorBySearchCursor[match] = pos
+		m.searchCursorByCursor[pos] = match
+	}
+}
+
+func (m *Model) setMatchMode(mode matchMode) {
+	m.matchMode = mode
+	m.searchInput.Prompt = "🔍 "
+	if mode != matchModeQuery {
+		m.searchInput.Prompt = fmt.Sprintf("🔍 [%s] ", mode)
+	}
+}
+
+func (m *Model) onSearchInit(filtering bool) {
 	m.searchMode = searchModeInit
 	m.searchInput.Reset()
 	m.searchInput.Focus()
 	m.searchResult = ""
+	m.searchErr = nil
+	m.filtering = filtering
+}
+
+// onFilter hides rows not matching the current search, or prompts for one.
+func (m *Model) onFilter() tea.Cmd {
+	if m.searchMode != searchModeFilter {
+		m.onSearchInit(true)
+		return nil
+	}
+
+	m.filtering = !m.filtering
+	return m.onTreeChanged()
 }
 
 func (m *Model) onSearchQuit() {
@@ -136,9 +264,15 @@ func (m *Model) onSearchQuit() {
 	m.searchInput.Reset()
 	m.searchMode = searchModeOff
 	m.searchResult = ""
+	m.search = nil
 	m.searchCursor = 0
 	m.cursorBySearchCursor = map[int]int{}
 	m.searchCursorByCursor = map[int]int{}
+	if m.filtering {
+		m.filtering = false
+		m.refresh(m.Current().ID)
+		return
+	}
 	m.doLoadTable()
 }
 
@@ -147,7 +281,13 @@ func (m *Model) onSearchNext() tea.Cmd {
 		return nil
 	}
 
+	sc, onMatch := m.searchCursorByCursor[m.cursor]
 	switch {
+	// Ranked results are visited by rank, starting from the best one
+	case m.isRanked() && onMatch:
+		m.searchCursor = sc + 1
+	case m.isRanked():
+		m.searchCursor = 0
 	// Behaviour within boundaries of search highlighted range
 	// If m.cursor is within the highlighted range, resets the position to the cursor itself.
 	// This will be the point of reference to be used.
@@ -180,7 +320,12 @@ func (m *Model) onSearchPrev() tea.Cmd {
 		return nil
 	}
 
+	sc, onMatch := m.searchCursorByCursor[m.cursor]
 	switch {
+	case m.isRanked() && onMatch:
+		m.searchCursor = sc - 1
+	case m.isRanked():
+		m.searchCursor = len(m.cursorBySearchCursor) - 1
 	// Behaviour within boundaries of search highlighted range
 	// If m.cursor is within the highlighted range, resets the position to the cursor itself.
 	// This will be the point of reference to be used.
@@ -208,43 +353,134 @@ func (m *Model) onSearchPrev() tea.Cmd {
 	}
 }
 
+func (m *Model) onOnlyUnhealthy() tea.Cmd {
+	m.onlyUnhealthy = !m.onlyUnhealthy
+	enabled := m.onlyUnhealthy
+	return tea.Batch(m.onTreeChanged(), func() tea.Msg {
+		return EventOnlyUnhealthyToggled{Enabled: enabled}
+	})
+}
+
+// SetOnlyUnhealthy restricts the rows to unhealthy ones, plus their ancestors
+// for context. It is kept across SetData calls.
+func (m *Model) SetOnlyUnhealthy(enabled bool) {
+	m.onlyUnhealthy = enabled
+	m.refresh(m.Current().ID)
+}
+
+// onMouse focuses clicked rows and sorts by clicked headers. Coordinates are
+// relative to the navigator, which has the table at its top.
+func (m *Model) onMouse(msg tea.MouseMsg) tea.Cmd {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return nil
+	}
+
+	if msg.Y == 0 {
+		if col, ok := m.table.ColumnAt(msg.X); ok {
+			m.onSort(col)
+		}
+		return nil
+	}
+
+	row, ok := m.table.RowAt(msg.Y)
+	if !ok || row == m.cursor {
+		return nil
+	}
+	return m.focus(row)
+}
+
+// FocusAncestor moves the cursor to the ancestor of the focused row at the
+// given depth (eg: 0 for the root).
+func (m *Model) FocusAncestor(depth int) tea.Cmd {
+	if len(m.visible) == 0 {
+		return nil
+	}
+
+	i := m.visible[m.cursor]
+	for i >= 0 && m.data[i].Depth > depth {
+		i = m.parentOf(i)
+	}
+	if pos := m.visiblePos(i); i >= 0 && pos >= 0 {
+		return m.focus(pos)
+	}
+	return nil
+}
+
+// focus moves the cursor to a visible position.
+func (m *Model) focus(pos int) tea.Cmd {
+	m.cursor = pos
+	m.table.SetCursor(pos)
+	m.doLoadTable()
+	return func() tea.Msg {
+		return EventItemFocused{ID: m.Current().ID, Data: m.Current().Data}
+	}
+}
+
+// onTreeChanged refreshes the visible rows, keeping focus on the current row.
+func (m *Model) onTreeChanged() tea.Cmd {
+	m.refresh(m.Current().ID)
+	return func() tea.Msg {
+		return EventItemFocused{ID: m.Current().ID, Data: m.Current().Data}
+	}
+}
+
 func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
 	if m.searchMode == searchModeInput {
 		return m.onSearch(msg)
 	}
+	if cmd, ok := m.onAction(msg); ok {
+		return cmd
+	}
 
 	switch {
 	case key.Matches(msg, m.KeyMap.Search):
-		m.onSearchInit()
+		m.onSearchInit(false)
+	case key.Matches(msg, m.KeyMap.Filter):
+		return m.onFilter()
 	case key.Matches(msg, m.KeyMap.Help):
 		m.showHelp = !m.showHelp
-		// m.Help.ShowAll = !m.Help.ShowAll
-	case key.Matches(msg, m.KeyMap.Copy):
-		return func() tea.Msg {
-			return EventItemCopied{ID: m.Current().ID, Data: m.Current().Data}
-		}
-	case key.Matches(msg, m.KeyMap.Get):
-		return func() tea.Msg {
-			return EventItemGet{ID: m.Current().ID, Data: m.Current().Data}
-		}
-	case key.Matches(msg, m.KeyMap.Delete):
-		return func() tea.Msg {
-			return EventItemDelete{ID: m.Current().ID, Data: m.Current().Data}
-		}
-	case key.Matches(msg, m.KeyMap.Edit):
-		return func() tea.Msg {
-			return EventItemEdit{ID: m.Current().ID, Data: m.Current().Data}
-		}
+	case key.Matches(msg, m.KeyMap.ShowFullHelp):
+		return func() tea.Msg { return EventShowFullHelp{} }
+	case key.Matches(msg, m.KeyMap.Select):
+		return m.onSelect()
+	case key.Matches(msg, m.KeyMap.SelectSubtree):
+		return m.onSelectSubtree()
+	case key.Matches(msg, m.KeyMap.SelectMatches):
+		return m.onSelectMatches()
 	case key.Matches(msg, m.KeyMap.SearchQuit):
-		m.onSearchQuit()
-	case key.Matches(msg, m.KeyMap.Describe):
-		return func() tea.Msg {
-			return EventItemDescribe{ID: m.Current().ID, Data: m.Current().Data}
+		// Selections are cleared only once there is no search to quit
+		if m.searchMode == searchModeOff {
+			return m.clearSelection()
 		}
+		m.onSearchQuit()
 	case key.Matches(msg, m.KeyMap.Quit):
 		return func() tea.Msg {
 			return EventQuitted{}
 		}
+	case key.Matches(msg, m.KeyMap.ToggleCollapse):
+		if len(m.visible) == 0 {
+			return nil
+		}
+		m.toggleCollapse(m.visible[m.cursor])
+		return m.onTreeChanged()
+	case key.Matches(msg, m.KeyMap.CollapseLevel):
+		m.collapseToDepth(max(m.visibleDepth()-1, 0))
+		return m.onTreeChanged()
+	case key.Matches(msg, m.KeyMap.ExpandLevel):
+		if depth := m.visibleDepth() + 1; depth < m.maxDepth() {
+			m.collapseToDepth(depth)
+		} else {
+			m.collapsed = map[string]bool{}
+		}
+		return m.onTreeChanged()
+	case key.Matches(msg, m.KeyMap.ExpandUnhealthy):
+		m.expandUnhealthy()
+		return m.onTreeChanged()
+	case key.Matches(msg, m.KeyMap.OnlyUnhealthy):
+		return m.onOnlyUnhealthy()
+	case key.Matches(msg, m.KeyMap.Wrap):
+		m.wrap = !m.wrap
+		m.setTableWrap()
 	case key.Matches(msg, m.KeyMap.SearchNext):
 		return m.onSearchNext()
 	case key.Matches(msg, m.KeyMap.SearchPrevious):
@@ -12,30 +12,54 @@ type KeyMap struct {
 	Quit        key.Binding
 
 	Search         key.Binding
+	Filter         key.Binding
 	SearchNext     key.Binding
 	SearchPrevious key.Binding
 	SearchConfirm  key.Binding
+	SearchMode     key.Binding
 	SearchQuit     key.Binding
 
-	Copy          key.Binding
-	Get           key.Binding
-	Edit          key.Binding
-	Delete        key.Binding
-	Describe      key.Binding
-	Help          key.Binding
-	CloseFullHelp key.Binding
+	Parent      key.Binding
+	FirstChild  key.Binding
+	NextSibling key.Binding
+	PrevSibling key.Binding
+	ZoomIn      key.Binding
+	ZoomOut     key.Binding
+
+	ToggleCollapse  key.Binding
+	CollapseLevel   key.Binding
+	ExpandLevel     key.Binding
+	ExpandUnhealthy key.Binding
+	OnlyUnhealthy   key.Binding
+	Wrap            key.Binding
+
+	Select        key.Binding
+	SelectSubtree key.Binding
+	SelectMatches key.Binding
+
+	Copy         key.Binding
+	Annotate     key.Binding
+	Pause        key.Binding
+	PauseSubtree key.Binding
+	Reconcile    key.Binding
+	Get          key.Binding
+	Edit         key.Binding
+	Delete       key.Binding
+	Describe     key.Binding
+	Help         key.Binding
+	ShowFullHelp key.Binding
 }
 
 // DefaultKeyMap returns a default set of keybindings.
 func DefaultKeyMap() KeyMap {
-	return KeyMap{
+	km := KeyMap{
 		Bottom: key.NewBinding(
-			key.WithKeys("bottom"),
-			key.WithHelp("end", "bottom"),
+			key.WithKeys("G"),
+			key.WithHelp("[n]G", "bottom (or line n)"),
 		),
 		Top: key.NewBinding(
-			key.WithKeys("top"),
-			key.WithHelp("home", "top"),
+			key.WithKeys("g"),
+			key.WithHelp("[n]gg", "top (or line n)"),
 		),
 		SectionDown: key.NewBinding(
 			key.WithKeys("secdown"),
@@ -54,31 +78,39 @@ func DefaultKeyMap() KeyMap {
 			key.WithHelp("↑/k", "up"),
 		),
 
-		Search: key.NewBinding(
-			key.WithKeys("/"),
-			key.WithHelp("/", "search"),
-		),
-		SearchNext: key.NewBinding(
-			key.WithKeys("n"),
-			key.WithHelp("n", "search next"),
+		Select: key.NewBinding(
+			key.WithKeys(" "),
+			key.WithHelp("space", "select"),
 		),
-		SearchPrevious: key.NewBinding(
-			key.WithKeys("N"),
-			key.WithHelp("N", "search previous"),
+		SelectSubtree: key.NewBinding(
+			key.WithKeys("v"),
+			key.WithHelp("v", "select subtree"),
 		),
-		SearchConfirm: key.NewBinding(
-			key.WithKeys("enter"),
-			key.WithHelp("enter", "search confirm"),
-		),
-		SearchQuit: key.NewBinding(
-			key.WithKeys("esc"),
-			key.WithHelp("esc", "search quit"),
+		SelectMatches: key.NewBinding(
+			key.WithKeys("*"),
+			key.WithHelp("*", "select matches"),
 		),
 
 		Copy: key.NewBinding(
 			key.WithKeys("c"),
 			key.WithHelp("c", "copy"),
 		),
+		Annotate: key.NewBinding(
+			key.WithKeys("a"),
+			key.WithHelp("a", "annotate"),
+		),
+		Pause: key.NewBinding(
+			key.WithKeys("P"),
+			key.WithHelp("P", "toggle pause"),
+		),
+		PauseSubtree: key.NewBinding(
+			key.WithKeys("ctrl+p"),
+			key.WithHelp("ctrl+p", "toggle pause (subtree)"),
+		),
+		Reconcile: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "reconcile now"),
+		),
 		Get: key.NewBinding(
 			key.WithKeys("y"),
 			key.WithHelp("y", "get (yaml)"),
@@ -95,8 +127,12 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("enter", "d"),
 			key.WithHelp("d", "describe")),
 		Help: key.NewBinding(
-			key.WithKeys("?", "h"),
-			key.WithHelp("?/h", "toogle help"),
+			key.WithKeys("h"),
+			key.WithHelp("h", "toggle help"),
+		),
+		ShowFullHelp: key.NewBinding(
+			key.WithKeys("?"),
+			key.WithHelp("?", "all keys"),
 		),
 
 		Quit: key.NewBinding(
@@ -104,4 +140,92 @@ func DefaultKeyMap() KeyMap {
 			key.WithHelp("q", "quit"),
 		),
 	}
+	setDefaultSearchKeys(&km)
+	setDefaultTreeKeys(&km)
+	return km
+}
+
+// setDefaultSearchKeys sets the default keybindings for searching and filtering.
+func setDefaultSearchKeys(km *KeyMap) {
+	km.Search = key.NewBinding(
+		key.WithKeys("/"),
+		key.WithHelp("/", "search"),
+	)
+	km.Filter = key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "filter"),
+	)
+	km.SearchNext = key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "search next"),
+	)
+	km.SearchPrevious = key.NewBinding(
+		key.WithKeys("N"),
+		key.WithHelp("N", "search previous"),
+	)
+	km.SearchConfirm = key.NewBinding(
+		key.WithKeys("enter"),
+		key.WithHelp("enter", "search confirm"),
+	)
+	km.SearchMode = key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "search mode (query/fuzzy/regex)"),
+	)
+	km.SearchQuit = key.NewBinding(
+		key.WithKeys("esc"),
+		key.WithHelp("esc", "search quit"),
+	)
+}
+
+// setDefaultTreeKeys sets the default keybindings for moving around the tree,
+// zooming into subtrees and changing how rows are shown.
+func setDefaultTreeKeys(km *KeyMap) {
+	km.Parent = key.NewBinding(
+		key.WithKeys("p", "shift+left"),
+		key.WithHelp("p", "parent"),
+	)
+	km.FirstChild = key.NewBinding(
+		key.WithKeys("o", "shift+right"),
+		key.WithHelp("o", "first child"),
+	)
+	km.NextSibling = key.NewBinding(
+		key.WithKeys("]", "shift+down"),
+		key.WithHelp("]", "next sibling"),
+	)
+	km.PrevSibling = key.NewBinding(
+		key.WithKeys("[", "shift+up"),
+		key.WithHelp("[", "previous sibling"),
+	)
+	km.ZoomIn = key.NewBinding(
+		key.WithKeys("z"),
+		key.WithHelp("z", "zoom into subtree"),
+	)
+	km.ZoomOut = key.NewBinding(
+		key.WithKeys("Z"),
+		key.WithHelp("Z", "zoom out"),
+	)
+	km.ToggleCollapse = key.NewBinding(
+		key.WithKeys("tab"),
+		key.WithHelp("tab", "collapse/expand"),
+	)
+	km.CollapseLevel = key.NewBinding(
+		key.WithKeys("-"),
+		key.WithHelp("-", "collapse level"),
+	)
+	km.ExpandLevel = key.NewBinding(
+		key.WithKeys("+", "="),
+		key.WithHelp("+", "expand level"),
+	)
+	km.ExpandUnhealthy = key.NewBinding(
+		key.WithKeys("!"),
+		key.WithHelp("!", "expand unhealthy"),
+	)
+	km.OnlyUnhealthy = key.NewBinding(
+		key.WithKeys("u"),
+		key.WithHelp("u", "only unhealthy"),
+	)
+	km.Wrap = key.NewBinding(
+		key.WithKeys("w"),
+		key.WithHelp("w", "wrap status"),
+	)
 }
@@ -3,10 +3,13 @@ package navigator
 import (
 	"fmt"
 	"log/slog"
+	"slices"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/brunoluiz/xpdig/internal/bubbles/component/table"
+	"github.com/brunoluiz/xpdig/internal/bubbles/keys"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
@@ -14,16 +17,28 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
 )
 
 type searchMode int
 
+// Matcher reports if a row matches a search.
+type Matcher func(DataRow) bool
+
+// MatcherFactory compiles a search query into a Matcher.
+type MatcherFactory func(query string) (Matcher, error)
+
+// DataRow is a row of a tree, with rows being in depth-first order. The first
+// column is the tree column, which gets the tree branches prefixed to it.
 type DataRow struct {
 	ID   string
 	Data any
 
-	Columns []string
-	Color   lipgloss.TerminalColor
+	Columns   []string
+	Style     lipgloss.Style
+	Depth     int
+	Unhealthy bool
+	Paused    bool
 }
 
 const (
@@ -61,18 +76,54 @@ type Model struct {
 	searchCursor         int
 	cursorBySearchCursor map[int]int
 	searchCursorByCursor map[int]int
+	filtering            bool
+	newMatcher           MatcherFactory
+	matchMode            matchMode
+	search               *compiledSearch
+	searchColumns        []int
+	searchErr            error
+	onlyUnhealthy        bool
+	wrap                 bool
+	wrapColumn           int
+
+	columns   []table.Column
+	sortTitle string
+	sortOrder sortOrder
+
+	unsorted  []DataRow
+	data      []DataRow
+	visible   []int
+	collapsed map[string]bool
+	selected  map[string]bool
+	zoom      []string
+
+	// count and pendingTop are used for vim-style movements (eg: `5j`, `gg`)
+	count      int
+	pendingTop bool
+}
+
+type WithOpt func(*Model)
+
+// WithKeyMap replaces the default keybindings.
+func WithKeyMap(km KeyMap) func(*Model) {
+	return func(m *Model) { m.KeyMap = km }
+}
 
-	data []DataRow
+// WithStyles replaces the default styles.
+func WithStyles(s Styles) func(*Model) {
+	return func(m *Model) { m.Styles = s }
 }
 
 func New(
 	logger *slog.Logger,
 	tableModel table.Model,
 	searchInputModel textinput.Model,
+	opts ...WithOpt,
 ) Model {
 	searchInputModel.Prompt = "🔍 "
 	searchInputModel.Placeholder = "Search..."
-	return Model{
+
+	m := Model{
 		logger:      logger,
 		table:       tableModel,
 		searchInput: searchInputModel,
@@ -88,6 +139,34 @@ func New(
 		searchCursor:         0,
 		cursorBySearchCursor: map[int]int{},
 		searchCursorByCursor: map[int]int{},
+
+		searchColumns: []int{0},
+		wrapColumn:    -1,
+		collapsed:     map[string]bool{},
+		selected:      map[string]bool{},
+	}
+	for _, opt := range opts {
+		opt(&m)
+	}
+	m.setTableKeyMap()
+	return m
+}
+
+// setTableKeyMap moves the table cursor with the navigator up/down keys, while
+// other navigator keys take precedence over the table ones (eg: `g`, as top
+// and bottom are handled by the navigator, since they support counts).
+func (m *Model) setTableKeyMap() {
+	actions := slices.DeleteFunc(keys.Actions("navigator", m.KeyMap), func(a keys.Action) bool {
+		return a.Name == "navigator.up" || a.Name == "navigator.down"
+	})
+
+	tk := &m.table.KeyMap
+	tk.LineUp, tk.LineDown = m.KeyMap.Up, m.KeyMap.Down
+	for _, b := range []*key.Binding{
+		&tk.PageUp, &tk.PageDown, &tk.HalfPageUp, &tk.HalfPageDown,
+		&tk.GotoTop, &tk.GotoBottom, &tk.ScrollLeft, &tk.ScrollRight,
+	} {
+		*b = keys.Without(*b, actions)
 	}
 }
 
@@ -110,10 +189,20 @@ func (m Model) View() string {
 		fallthrough
 	case searchModeInput:
 		searchBar := lipgloss.NewStyle().Render(m.searchInput.View())
+		if m.searchErr != nil {
+			searchBar = lipgloss.JoinHorizontal(lipgloss.Top, searchBar, "  ", m.Styles.Error.Render("⚠ "+m.searchErr.Error()))
+		}
 		availableHeight -= lipgloss.Height(searchBar)
 		components = append(components, searchBar)
 	case searchModeFilter:
-		filterBar := lipgloss.NewStyle().Render(fmt.Sprintf("🔍 Showing results for: %s", m.searchInput.Value()))
+		label := "Showing results for"
+		if m.filtering {
+			label = "Filtering by"
+		}
+		if m.matchMode != matchModeQuery {
+			label += fmt.Sprintf(" (%s)", m.matchMode)
+		}
+		filterBar := lipgloss.NewStyle().Render(fmt.Sprintf("🔍 %s: %s", label, m.searchInput.Value()))
 		availableHeight -= lipgloss.Height(filterBar)
 		components = append(components, filterBar)
 	}
@@ -124,27 +213,96 @@ func (m Model) View() string {
 	return lipgloss.JoinVertical(lipgloss.Left, append([]string{tree}, components...)...)
 }
 
+// SetData replaces the tree rows, keeping the cursor on the same row ID if
+// it is still around. Collapsed rows are kept collapsed, based on their IDs,
+// and siblings are kept sorted if sorting by a column.
 func (m *Model) SetData(data []DataRow) {
+	focused := m.Current().ID
+	m.unsorted = data
 	m.data = data
+	if col := m.sortColumn(); col >= 0 {
+		m.data = sortRows(data, col, m.sortOrder)
+	}
+	m.pruneSelection()
+	m.refresh(focused)
+}
+
+// refresh recomputes which rows are visible and moves the cursor to the row
+// with the focused ID, or its closest visible ancestor if it got hidden.
+func (m *Model) refresh(focused string) {
+	m.visible = m.computeVisible()
+
+	if focused != "" {
+		for i, v := range m.data {
+			if v.ID != focused {
+				continue
+			}
+			for ; i >= 0; i = m.parentOf(i) {
+				if pos := m.visiblePos(i); pos >= 0 {
+					m.cursor = pos
+					break
+				}
+			}
+			break
+		}
+	}
+	m.cursor = max(min(m.cursor, len(m.visible)-1), 0)
+
+	if m.searchMode == searchModeFilter {
+		m.indexSearch()
+	}
 	m.doLoadTable()
+	m.table.SetCursor(m.cursor)
+}
+
+func (m Model) visiblePos(i int) int {
+	for pos, v := range m.visible {
+		if v == i {
+			return pos
+		}
+	}
+	return -1
 }
 
 func (m *Model) doLoadTable() {
 	rows := []table.Row{}
-	searchTerm := strings.ToLower(m.searchInput.Value())
-	for k, v := range m.data {
+	prefixes := m.treePrefixes(m.visible)
+	if len(m.selected) > 0 {
+		for k, i := range m.visible {
+			if m.selected[m.data[i].ID] {
+				prefixes[k] = selectionMarker + prefixes[k]
+			} else {
+				prefixes[k] = strings.Repeat(" ", runewidth.StringWidth(selectionMarker)) + prefixes[k]
+			}
+		}
+	}
+	for k, i := range m.visible {
+		v := m.data[i]
 		s := lipgloss.NewStyle()
 		if m.cursor != k {
-			s = s.Foreground(v.Color)
+			s = v.Style
 		}
 
-		if m.searchMode == searchModeFilter && strings.Contains(strings.ToLower(v.ID), searchTerm) {
-			s = s.Bold(true).Foreground(lipgloss.Color("15")).Background(lipgloss.Color("1")) // White on red
+		highlight := m.searchMode == searchModeFilter && m.matches(v)
+		if highlight && m.search.positions == nil {
+			s = m.Styles.Match
 		}
 
 		cols := []table.Cell{}
-		for _, col := range v.Columns {
-			cols = append(cols, table.Cell{Value: col, Style: s})
+		for c, col := range v.Columns {
+			cell := table.Cell{Value: col, Style: s}
+			offset := 0
+			if c == 0 {
+				offset = utf8.RuneCountInString(prefixes[k])
+				cell.Value = prefixes[k] + col
+				if m.collapsed[v.ID] && m.hasChildren(i) {
+					cell.Value += m.collapsedSummary(i)
+				}
+			}
+			if highlight && m.search.positions != nil && slices.Contains(m.searchColumns, c) {
+				cell.Spans = m.highlightSpans(col, offset)
+			}
+			cols = append(cols, cell)
 		}
 
 		rows = append(rows, cols)
@@ -154,9 +312,55 @@ func (m *Model) doLoadTable() {
 	m.table.Focus()
 }
 
+// matches reports if the row matches the confirmed search.
+func (m Model) matches(v DataRow) bool {
+	return m.search != nil && m.search.match(v)
+}
+
+func (m Model) isRanked() bool {
+	return m.search != nil && m.search.score != nil
+}
+
+// SetSearchColumns sets which columns are searched and highlighted by the
+// fuzzy and regex match modes.
+func (m *Model) SetSearchColumns(cols []int) {
+	m.searchColumns = cols
+}
+
+// SetWrapColumn sets which column of the focused row gets wrapped across
+// multiple lines when wrapping is toggled, with -1 disabling it.
+func (m *Model) SetWrapColumn(col int) {
+	m.wrapColumn = col
+	m.setTableWrap()
+}
+
+func (m *Model) setTableWrap() {
+	if m.wrap {
+		m.table.SetWrapColumn(m.wrapColumn)
+		return
+	}
+	m.table.SetWrapColumn(-1)
+}
+
+// SetMatcherFactory sets how search queries are compiled.
+func (m *Model) SetMatcherFactory(f MatcherFactory) {
+	m.newMatcher = f
+}
+
 func (m *Model) SetColumns(cc []table.Column) {
-	m.table.SetColumns(cc)
-	cols := m.table.Columns()
+	m.columns = cc
+
+	// Copied, as titles get the sort indicator and the last width is stretched
+	cols := slices.Clone(cc)
+	if col := m.sortColumn(); col >= 0 {
+		indicator := " ▲"
+		if m.sortOrder == sortDesc {
+			indicator = " ▼"
+		}
+		// Shortening the title if needed, so the indicator is always visible
+		title := runewidth.Truncate(cols[col].Title, cols[col].Width-runewidth.StringWidth(indicator), "")
+		cols[col].Title = title + indicator
+	}
 
 	// Adding `2` due to borders and all
 	if len(cols) > 2 {
@@ -166,6 +370,7 @@ func (m *Model) SetColumns(cc []table.Column) {
 		}
 		cols[len(cols)-1].Width = (m.width - w + 2)
 	}
+	m.table.SetColumns(cols)
 }
 
 func (m Model) ShortHelp() []key.Binding {
@@ -173,17 +378,35 @@ func (m Model) ShortHelp() []key.Binding {
 	return append([]key.Binding{},
 		k.Up, k.Down, k.Copy,
 		k.Describe, k.Get, k.Edit, k.Delete,
-		k.Search, k.Help, k.Quit,
+		k.Search, k.OnlyUnhealthy, k.ShowFullHelp, k.Quit,
 	)
 }
 
 func (m Model) FullHelp() [][]key.Binding {
 	k := m.KeyMap
-	kb := [][]key.Binding{{k.Up, k.Down, k.Copy, k.Describe}}
-	return append(kb, []key.Binding{k.Quit, k.CloseFullHelp})
+	return [][]key.Binding{
+		{k.Up, k.Down, k.Top, k.Bottom, k.Parent, k.FirstChild, k.NextSibling, k.PrevSibling},
+		{k.ToggleCollapse, k.CollapseLevel, k.ExpandLevel, k.ExpandUnhealthy, k.OnlyUnhealthy, k.ZoomIn, k.ZoomOut, k.Wrap},
+		{k.Search, k.Filter, k.SearchNext, k.SearchPrevious, k.SearchMode, k.SearchConfirm, k.SearchQuit},
+		{k.Select, k.SelectSubtree, k.SelectMatches},
+		{k.Describe, k.Get, k.Edit, k.Delete, k.Copy, k.Annotate, k.Pause, k.PauseSubtree, k.Reconcile},
+		{k.Help, k.ShowFullHelp, k.Quit},
+	}
+}
+
+// TableKeyMap returns the table bindings, which the navigator ones take
+// precedence over.
+func (m Model) TableKeyMap() table.KeyMap { return m.table.KeyMap }
+
+// Current returns the focused row, which is empty if no rows are visible.
+func (m Model) Current() *DataRow {
+	if m.cursor < 0 || m.cursor >= len(m.visible) {
+		return &DataRow{}
+	}
+	return &m.data[m.visible[m.cursor]]
 }
 
-func (m Model) Current() *DataRow          { return &m.data[m.cursor] }
+func (m Model) InputFocused() bool         { return m.searchInput.Focused() }
 func (m *Model) setSize(width, height int) { m.width = width; m.height = height }
 
 func (m Model) helpView() string {
@@ -0,0 +1,171 @@
+package navigator
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// onNavKey handles tree-aware and vim-style movements, which might be prefixed
+// by a count (eg: `5j`, `10gg`). It reports if the key was handled, so it is
+// not handled by the table as well.
+func (m *Model) onNavKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	if msg.Type == tea.KeyRunes && len(msg.Runes) == 1 && msg.Runes[0] >= '0' && msg.Runes[0] <= '9' {
+		digit := int(msg.Runes[0] - '0')
+		if m.count == 0 && digit == 0 {
+			return nil, false
+		}
+		m.count = min(m.count*10+digit, maxCount)
+		m.pendingTop = false
+		return nil, true
+	}
+
+	count, pendingTop := m.count, m.pendingTop
+	m.count, m.pendingTop = 0, false
+
+	switch {
+	case key.Matches(msg, m.KeyMap.Top):
+		if !pendingTop {
+			m.count, m.pendingTop = count, true
+			return nil, true
+		}
+		return m.focusLine(count, 0), true
+	case key.Matches(msg, m.KeyMap.Bottom):
+		return m.focusLine(count, len(m.visible)-1), true
+	case count > 0 && key.Matches(msg, m.KeyMap.Down):
+		return m.focusLine(m.cursor+count+1, 0), true
+	case count > 0 && key.Matches(msg, m.KeyMap.Up):
+		return m.focusLine(max(m.cursor-count+1, 1), 0), true
+	case key.Matches(msg, m.KeyMap.Parent):
+		return m.onParent(), true
+	case key.Matches(msg, m.KeyMap.FirstChild):
+		return m.onFirstChild(), true
+	case key.Matches(msg, m.KeyMap.NextSibling):
+		return m.onSibling(1), true
+	case key.Matches(msg, m.KeyMap.PrevSibling):
+		return m.onSibling(-1), true
+	case key.Matches(msg, m.KeyMap.ZoomIn):
+		return m.onZoomIn(), true
+	case key.Matches(msg, m.KeyMap.ZoomOut):
+		return m.onZoomOut(), true
+	}
+	return nil, false
+}
+
+// maxCount caps counts, as there is no point in them being bigger than the tree.
+const maxCount = 99999
+
+// focusLine focuses a 1-based line, or the fallback position if line is 0.
+func (m *Model) focusLine(line, fallback int) tea.Cmd {
+	if len(m.visible) == 0 {
+		return nil
+	}
+
+	pos := fallback
+	if line > 0 {
+		pos = line - 1
+	}
+	return m.focus(max(min(pos, len(m.visible)-1), 0))
+}
+
+func (m *Model) onParent() tea.Cmd {
+	if len(m.visible) == 0 {
+		return nil
+	}
+
+	if pos := m.visiblePos(m.parentOf(m.visible[m.cursor])); pos >= 0 {
+		return m.focus(pos)
+	}
+	return nil
+}
+
+// onFirstChild focuses the first child, expanding the row if it is collapsed.
+func (m *Model) onFirstChild() tea.Cmd {
+	if len(m.visible) == 0 {
+		return nil
+	}
+
+	i := m.visible[m.cursor]
+	if m.collapsed[m.data[i].ID] {
+		delete(m.collapsed, m.data[i].ID)
+		m.refresh(m.data[i].ID)
+	}
+
+	next := m.cursor + 1
+	if next < len(m.visible) && m.data[m.visible[next]].Depth > m.data[i].Depth {
+		return m.focus(next)
+	}
+	return nil
+}
+
+// onSibling focuses the next (dir = 1) or previous (dir = -1) visible sibling.
+func (m *Model) onSibling(dir int) tea.Cmd {
+	if len(m.visible) == 0 {
+		return nil
+	}
+
+	depth := m.data[m.visible[m.cursor]].Depth
+	for pos := m.cursor + dir; pos >= 0 && pos < len(m.visible); pos += dir {
+		switch d := m.data[m.visible[pos]].Depth; {
+		case d == depth:
+			return m.focus(pos)
+		case d < depth:
+			return nil
+		}
+	}
+	return nil
+}
+
+// onZoomIn re-roots the view on the focused subtree.
+func (m *Model) onZoomIn() tea.Cmd {
+	current := m.Current()
+	if current.ID == "" || (len(m.zoom) > 0 && m.zoom[len(m.zoom)-1] == current.ID) {
+		return nil
+	}
+
+	m.zoom = append(m.zoom, current.ID)
+	return tea.Batch(m.onTreeChanged(), m.zoomed())
+}
+
+// onZoomOut goes back to the previous root, keeping the cursor on the current one.
+func (m *Model) onZoomOut() tea.Cmd {
+	if len(m.zoom) == 0 {
+		return nil
+	}
+
+	root := m.zoom[len(m.zoom)-1]
+	m.zoom = m.zoom[:len(m.zoom)-1]
+	m.refresh(root)
+	return tea.Batch(func() tea.Msg {
+		return EventItemFocused{ID: m.Current().ID, Data: m.Current().Data}
+	}, m.zoomed())
+}
+
+func (m Model) zoomed() tea.Cmd {
+	root := m.zoomRoot()
+	id := ""
+	if root >= 0 {
+		id = m.data[root].ID
+	}
+	return func() tea.Msg { return EventZoomed{ID: id} }
+}
+
+// zoomRoot returns the data index of the row the view is zoomed into, or -1.
+// Roots that disappeared after a refresh are dropped.
+func (m Model) zoomRoot() int {
+	for z := len(m.zoom) - 1; z >= 0; z-- {
+		for i, v := range m.data {
+			if v.ID == m.zoom[z] {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// baseDepth is the depth of the current root, so zoomed trees are not indented.
+func (m Model) baseDepth() int {
+	if root := m.zoomRoot(); root >= 0 {
+		return m.data[root].Depth
+	}
+	return 0
+}
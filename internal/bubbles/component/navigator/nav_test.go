@@ -0,0 +1,112 @@
+package navigator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOnSibling(t *testing.T) {
+	type args struct {
+		focused int
+		dir     int
+	}
+
+	type want struct {
+		focused int
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Next": {
+			reason: "Should focus the next sibling",
+			args:   args{focused: 3, dir: 1},
+			want:   want{focused: 4},
+		},
+		"NextOverDescendants": {
+			reason: "Should focus the next sibling, skipping the descendants in between",
+			args:   args{focused: 2, dir: 1},
+			want:   want{focused: 7},
+		},
+		"Previous": {
+			reason: "Should focus the previous sibling, skipping its descendants",
+			args:   args{focused: 7, dir: -1},
+			want:   want{focused: 2},
+		},
+		"Last": {
+			reason: "Should not leave the parent after the last sibling",
+			args:   args{focused: 7, dir: 1},
+			want:   want{focused: 7},
+		},
+		"First": {
+			reason: "Should not leave the parent before the first sibling",
+			args:   args{focused: 2, dir: -1},
+			want:   want{focused: 2},
+		},
+		"Subtrees": {
+			reason: "Should focus the next sibling subtree",
+			args:   args{focused: 1, dir: 1},
+			want:   want{focused: 8},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := newTestModel(t)
+			m.cursor = m.visiblePos(tc.args.focused)
+
+			m.onSibling(tc.args.dir)
+			if got := m.visible[m.cursor]; got != tc.want.focused {
+				t.Errorf("\n%s\nonSibling(%d): want row %d focused, got %d", tc.reason, tc.args.dir, tc.want.focused, got)
+			}
+		})
+	}
+}
+
+func TestZoomRoot(t *testing.T) {
+	type args struct {
+		zoom []string
+	}
+
+	type want struct {
+		root    int
+		visible []int
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Zoomed": {
+			reason: "Should only show the subtree of the row zoomed into",
+			args:   args{zoom: []string{"2:Bucket/test-resource-bucket-hash"}},
+			want:   want{root: 2, visible: []int{2, 3, 4, 5, 6}},
+		},
+		"Nested": {
+			reason: "Should only show the subtree of the last row zoomed into",
+			args:   args{zoom: []string{"1:XObjectStorage/test-resource-hash", "2:Bucket/test-resource-bucket-hash"}},
+			want:   want{root: 2, visible: []int{2, 3, 4, 5, 6}},
+		},
+		"Gone": {
+			reason: "Should drop rows zoomed into which are gone, going back to the previous one",
+			args:   args{zoom: []string{"1:XObjectStorage/test-resource-hash", "2:Bucket/missing"}},
+			want:   want{root: 1, visible: []int{1, 2, 3, 4, 5, 6, 7}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := newTestModel(t)
+			m.zoom = tc.args.zoom
+			m.refresh("")
+
+			if got := m.zoomRoot(); got != tc.want.root {
+				t.Errorf("\n%s\nzoomRoot(): want %d, got %d", tc.reason, tc.want.root, got)
+			}
+			if got := positions(m.data, m.visible); !slices.Equal(tc.want.visible, got) {
+				t.Errorf("\n%s\ncomputeVisible(): want %v, got %v", tc.reason, tc.want.visible, got)
+			}
+		})
+	}
+}
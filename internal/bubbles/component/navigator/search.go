@@ -0,0 +1,186 @@
+package navigator
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/table"
+	"github.com/sahilm/fuzzy"
+)
+
+type matchMode int
+
+const (
+	matchModeQuery matchMode = iota
+	matchModeFuzzy
+	matchModeRegex
+)
+
+func (mm matchMode) String() string {
+	switch mm {
+	case matchModeFuzzy:
+		return "fuzzy"
+	case matchModeRegex:
+		return "regex"
+	default:
+		return "query"
+	}
+}
+
+func (mm matchMode) next() matchMode {
+	return (mm + 1) % (matchModeRegex + 1)
+}
+
+// compiledSearch is a search ready to be matched against rows.
+type compiledSearch struct {
+	match Matcher
+	// positions returns which runes of a cell value matched, with a nil func
+	// meaning that whole rows are highlighted instead
+	positions func(value string) []int
+	// score ranks matching rows, with higher scores visited first by next/previous,
+	// while a nil func keeps the tree order
+	score func(v DataRow) int
+}
+
+// compileSearch turns the search input into a matcher, according to the match
+// mode. The query mode defaults to a case-insensitive substring match on IDs
+// if no matcher factory is set.
+func (m Model) compileSearch() (*compiledSearch, error) {
+	q := m.searchInput.Value()
+	switch m.matchMode {
+	case matchModeFuzzy:
+		return m.compileFuzzy(q), nil
+	case matchModeRegex:
+		return m.compileRegex(q)
+	}
+
+	if m.newMatcher == nil {
+		term := strings.ToLower(q)
+		return &compiledSearch{match: func(v DataRow) bool {
+			return strings.Contains(strings.ToLower(v.ID), term)
+		}}, nil
+	}
+
+	matcher, err := m.newMatcher(q)
+	if err != nil {
+		return nil, err
+	}
+	return &compiledSearch{match: matcher}, nil
+}
+
+func (m Model) compileFuzzy(q string) *compiledSearch {
+	positions := func(value string) []int {
+		matches := fuzzy.Find(q, []string{value})
+		if len(matches) == 0 {
+			return nil
+		}
+		return runePositions(value, matches[0].MatchedIndexes)
+	}
+
+	score := func(v DataRow) int {
+		best, found := 0, false
+		for _, value := range m.searchValues(v) {
+			for _, match := range fuzzy.Find(q, []string{value}) {
+				if !found || match.Score > best {
+					best, found = match.Score, true
+				}
+			}
+		}
+		return best
+	}
+
+	return &compiledSearch{
+		match: func(v DataRow) bool {
+			for _, value := range m.searchValues(v) {
+				if len(fuzzy.Find(q, []string{value})) > 0 {
+					return true
+				}
+			}
+			return false
+		},
+		positions: positions,
+		score:     score,
+	}
+}
+
+func (m Model) compileRegex(q string) (*compiledSearch, error) {
+	re, err := regexp.Compile("(?i)" + q)
+	if err != nil {
+		return nil, fmt.Errorf("invalid regular expression: %w", err)
+	}
+
+	return &compiledSearch{
+		match: func(v DataRow) bool {
+			for _, value := range m.searchValues(v) {
+				if re.MatchString(value) {
+					return true
+				}
+			}
+			return false
+		},
+		positions: func(value string) []int {
+			bytePositions := []int{}
+			for _, loc := range re.FindAllStringIndex(value, -1) {
+				for i := loc[0]; i < loc[1]; i++ {
+					bytePositions = append(bytePositions, i)
+				}
+			}
+			return runePositions(value, bytePositions)
+		},
+	}, nil
+}
+
+// searchValues returns the values of the columns searched by fuzzy and regex modes.
+func (m Model) searchValues(v DataRow) []string {
+	values := []string{}
+	for _, c := range m.searchColumns {
+		if c < len(v.Columns) {
+			values = append(values, v.Columns[c])
+		}
+	}
+	return values
+}
+
+// rankMatches orders visible positions of matches by score, if ranked.
+func (m Model) rankMatches(positions []int) []int {
+	if m.search == nil || m.search.score == nil {
+		return positions
+	}
+
+	scores := map[int]int{}
+	for _, pos := range positions {
+		scores[pos] = m.search.score(m.data[m.visible[pos]])
+	}
+	sort.SliceStable(positions, func(i, j int) bool {
+		return scores[positions[i]] > scores[positions[j]]
+	})
+	return positions
+}
+
+// highlightSpans returns spans highlighting matched runes of a cell value,
+// shifted by offset runes (eg: tree branches prefixed to the value).
+func (m Model) highlightSpans(value string, offset int) []table.Span {
+	spans := []table.Span{}
+	for _, pos := range m.search.positions(value) {
+		if n := len(spans); n > 0 && spans[n-1].End == pos+offset {
+			spans[n-1].End++
+			continue
+		}
+		spans = append(spans, table.Span{Start: pos + offset, End: pos + offset + 1, Style: m.Styles.Match})
+	}
+	return spans
+}
+
+// runePositions converts byte positions of s into rune positions.
+func runePositions(s string, bytePositions []int) []int {
+	positions := make([]int, 0, len(bytePositions))
+	for _, b := range bytePositions {
+		if b <= len(s) {
+			positions = append(positions, utf8.RuneCountInString(s[:b]))
+		}
+	}
+	return positions
+}
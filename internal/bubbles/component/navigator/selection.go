@@ -0,0 +1,198 @@
+package navigator
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Item is an object which actions are run against.
+type Item struct {
+	ID   string
+	Data any
+}
+
+// selectionMarker is prefixed to selected rows, with unselected rows being
+// padded instead, so the tree stays aligned.
+const selectionMarker = "● "
+
+// items returns the selected rows in tree order or, if none is selected, the
+// focused row. Objects showing up more than once in the tree are only
+// returned once.
+func (m Model) items() []Item {
+	items := []Item{}
+	seen := map[string]bool{}
+	for _, v := range m.data {
+		if m.selected[v.ID] && !seen[v.ID] {
+			seen[v.ID] = true
+			items = append(items, Item{ID: v.ID, Data: v.Data})
+		}
+	}
+	if len(items) == 0 && m.Current().ID != "" {
+		items = append(items, Item{ID: m.Current().ID, Data: m.Current().Data})
+	}
+	return items
+}
+
+func (m *Model) onSelect() tea.Cmd {
+	id := m.Current().ID
+	if id == "" {
+		return nil
+	}
+
+	if m.selected[id] {
+		delete(m.selected, id)
+	} else {
+		m.selected[id] = true
+	}
+	return m.onSelectionChanged()
+}
+
+// onSelectSubtree selects the focused row and all its descendants, or
+// unselects them if the focused row is already selected.
+func (m *Model) onSelectSubtree() tea.Cmd {
+	if len(m.visible) == 0 {
+		return nil
+	}
+
+	i := m.visible[m.cursor]
+	selected := !m.selected[m.data[i].ID]
+	for _, v := range m.data[i:m.subtreeEnd(i)] {
+		if selected {
+			m.selected[v.ID] = true
+		} else {
+			delete(m.selected, v.ID)
+		}
+	}
+	return m.onSelectionChanged()
+}
+
+// onSelectMatches selects all rows matching the confirmed search.
+func (m *Model) onSelectMatches() tea.Cmd {
+	if m.search == nil {
+		return nil
+	}
+
+	for _, v := range m.data {
+		if m.matches(v) {
+			m.selected[v.ID] = true
+		}
+	}
+	return m.onSelectionChanged()
+}
+
+func (m *Model) clearSelection() tea.Cmd {
+	if len(m.selected) == 0 {
+		return nil
+	}
+	m.selected = map[string]bool{}
+	return m.onSelectionChanged()
+}
+
+// pruneSelection forgets selected rows which are not around anymore.
+func (m *Model) pruneSelection() {
+	ids := map[string]bool{}
+	for _, v := range m.data {
+		ids[v.ID] = true
+	}
+	for id := range m.selected {
+		if !ids[id] {
+			delete(m.selected, id)
+		}
+	}
+}
+
+func (m *Model) onSelectionChanged() tea.Cmd {
+	m.doLoadTable()
+	count := len(m.selected)
+	return func() tea.Msg { return EventSelectionChanged{Count: count} }
+}
+
+// SelectionCount returns how many rows are selected.
+func (m Model) SelectionCount() int { return len(m.selected) }
+
+// confirmed asks for a confirmation before sending msg when acting on more
+// than one item, as it is easy to forget what is selected.
+func confirmed(action string, items []Item, msg tea.Msg) tea.Cmd {
+	if len(items) < 2 {
+		return func() tea.Msg { return msg }
+	}
+	return func() tea.Msg {
+		return EventConfirm{
+			Title:     confirmTitle(action, items),
+			Items:     items,
+			OnConfirm: func(string) tea.Msg { return msg },
+		}
+	}
+}
+
+func confirmTitle(action string, items []Item) string {
+	if len(items) == 1 {
+		return fmt.Sprintf("%s %s?", action, items[0].ID)
+	}
+	return fmt.Sprintf("%s %d objects?", action, len(items))
+}
+
+func (m *Model) onAnnotate() tea.Cmd {
+	items := m.items()
+	if len(items) == 0 {
+		return nil
+	}
+	return func() tea.Msg {
+		return EventConfirm{
+			Title: confirmTitle("Annotate", items),
+			Items: items,
+			Input: "key=value key2=value2 (key- removes it)",
+			OnConfirm: func(input string) tea.Msg {
+				return EventItemAnnotate{Items: items, Annotations: strings.Fields(input)}
+			},
+		}
+	}
+}
+
+// subtreeItems returns the focused row and all its descendants, regardless
+// of what is selected or visible.
+func (m Model) subtreeItems() []Item {
+	if len(m.visible) == 0 {
+		return nil
+	}
+
+	items := []Item{}
+	seen := map[string]bool{}
+	i := m.visible[m.cursor]
+	for _, v := range m.data[i:m.subtreeEnd(i)] {
+		if !seen[v.ID] {
+			seen[v.ID] = true
+			items = append(items, Item{ID: v.ID, Data: v.Data})
+		}
+	}
+	return items
+}
+
+// onPause toggles the reconciliation of items: these are resumed if all of
+// them are paused, otherwise these are all paused.
+func (m *Model) onPause(items []Item) tea.Cmd {
+	if len(items) == 0 {
+		return nil
+	}
+
+	paused := map[string]bool{}
+	for _, v := range m.data {
+		paused[v.ID] = v.Paused
+	}
+	pause := slices.ContainsFunc(items, func(item Item) bool { return !paused[item.ID] })
+
+	action := "Resume"
+	if pause {
+		action = "Pause"
+	}
+	return func() tea.Msg {
+		return EventConfirm{
+			Title:     confirmTitle(action, items),
+			Items:     items,
+			OnConfirm: func(string) tea.Msg { return EventItemPause{Items: items, Paused: pause} },
+		}
+	}
+}
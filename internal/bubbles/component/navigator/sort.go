@@ -0,0 +1,85 @@
+package navigator
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/table"
+)
+
+type sortOrder int
+
+const (
+	sortOff sortOrder = iota
+	sortAsc
+	sortDesc
+)
+
+// onSort sorts siblings by a column, cycling between ascending, descending
+// and the original order on successive calls for the same column.
+func (m *Model) onSort(col int) {
+	if col < 0 || col >= len(m.columns) {
+		return
+	}
+
+	title := m.columns[col].Title
+	switch {
+	case m.sortTitle != title:
+		m.sortTitle, m.sortOrder = title, sortAsc
+	case m.sortOrder == sortAsc:
+		m.sortOrder = sortDesc
+	default:
+		m.sortTitle, m.sortOrder = "", sortOff
+	}
+
+	m.SetColumns(m.columns)
+	m.SetData(m.unsorted)
+}
+
+// sortColumn returns the index of the column rows are sorted by, or -1.
+func (m Model) sortColumn() int {
+	if m.sortOrder == sortOff {
+		return -1
+	}
+	return slices.IndexFunc(m.columns, func(c table.Column) bool { return c.Title == m.sortTitle })
+}
+
+// sortRows sorts siblings by a column, keeping each of them with its subtree.
+func sortRows(rows []DataRow, col int, order sortOrder) []DataRow {
+	if len(rows) == 0 {
+		return rows
+	}
+
+	subtrees := [][]DataRow{}
+	for i, v := range rows {
+		if i == 0 || v.Depth <= rows[0].Depth {
+			subtrees = append(subtrees, []DataRow{})
+		}
+		subtrees[len(subtrees)-1] = append(subtrees[len(subtrees)-1], v)
+	}
+
+	for i, st := range subtrees {
+		subtrees[i] = append([]DataRow{st[0]}, sortRows(st[1:], col, order)...)
+	}
+
+	slices.SortStableFunc(subtrees, func(a, b []DataRow) int {
+		cmp := strings.Compare(strings.ToLower(cellValue(a[0], col)), strings.ToLower(cellValue(b[0], col)))
+		if order == sortDesc {
+			return -cmp
+		}
+		return cmp
+	})
+
+	sorted := make([]DataRow, 0, len(rows))
+	for _, st := range subtrees {
+		sorted = append(sorted, st...)
+	}
+	return sorted
+}
+
+func cellValue(v DataRow, col int) string {
+	if col < len(v.Columns) {
+		return v.Columns[col]
+	}
+	return ""
+}
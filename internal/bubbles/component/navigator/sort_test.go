@@ -0,0 +1,62 @@
+package navigator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestOnSort(t *testing.T) {
+	type args struct {
+		col   int
+		times int
+	}
+
+	type want struct {
+		offsets []int
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Ascending": {
+			reason: "Should sort siblings in ascending order, keeping them with their subtrees",
+			args:   args{col: 1, times: 1},
+			want:   want{offsets: []int{0, 1, 3, 2, 4, 5, 6}},
+		},
+		"Descending": {
+			reason: "Should sort siblings in descending order once sorted again by the same column",
+			args:   args{col: 1, times: 2},
+			want:   want{offsets: []int{0, 6, 1, 2, 4, 5, 3}},
+		},
+		"Original": {
+			reason: "Should go back to the original order once sorted a third time by the same column",
+			args:   args{col: 1, times: 3},
+			want:   want{offsets: []int{0, 1, 2, 3, 4, 5, 6}},
+		},
+		"Stable": {
+			reason: "Should keep the original order of siblings with the same value",
+			args:   args{col: 2, times: 1},
+			want:   want{offsets: []int{0, 1, 2, 4, 5, 3, 6}},
+		},
+		"UnknownColumn": {
+			reason: "Should not sort by columns that do not exist",
+			args:   args{col: 3, times: 1},
+			want:   want{offsets: []int{0, 1, 2, 3, 4, 5, 6}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := newTestModel(t)
+			for range tc.args.times {
+				m.onSort(tc.args.col)
+			}
+
+			want := repeated(len(m.data), tc.want.offsets...)
+			if got := positions(m.data, m.computeVisible()); !slices.Equal(want, got) {
+				t.Errorf("\n%s\nonSort(%d): want %v, got %v", tc.reason, tc.args.col, want, got)
+			}
+		})
+	}
+}
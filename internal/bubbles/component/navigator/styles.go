@@ -3,11 +3,15 @@ package navigator
 import "github.com/charmbracelet/lipgloss"
 
 type Styles struct {
-	Help lipgloss.Style
+	Help  lipgloss.Style
+	Error lipgloss.Style
+	Match lipgloss.Style
 }
 
 func DefaultStyles() Styles {
 	return Styles{
-		Help: lipgloss.NewStyle().Margin(0, 0, 0, 0).Foreground(lipgloss.AdaptiveColor{Light: "#333", Dark: "#eee"}),
+		Help:  lipgloss.NewStyle().Margin(0, 0, 0, 0).Foreground(lipgloss.AdaptiveColor{Light: "#333", Dark: "#eee"}),
+		Error: lipgloss.NewStyle().Foreground(lipgloss.Color("1")),
+		Match: lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("15")).Background(lipgloss.Color("1")),
 	}
 }
@@ -0,0 +1,192 @@
+package navigator
+
+import (
+	"fmt"
+	"slices"
+)
+
+// subtreeEnd returns the data index right after the last descendant of i.
+func (m Model) subtreeEnd(i int) int {
+	end := i + 1
+	for end < len(m.data) && m.data[end].Depth > m.data[i].Depth {
+		end++
+	}
+	return end
+}
+
+// parentOf returns the data index of the parent of i, or -1 for roots.
+func (m Model) parentOf(i int) int {
+	for p := i - 1; p >= 0; p-- {
+		if m.data[p].Depth < m.data[i].Depth {
+			return p
+		}
+	}
+	return -1
+}
+
+func (m Model) hasChildren(i int) bool {
+	return m.subtreeEnd(i) > i+1
+}
+
+// computeVisible returns which data indexes are shown, skipping descendants
+// of collapsed rows. While filtering or showing only unhealthy rows, only rows
+// kept by these and their ancestors are shown instead, regardless of them
+// being collapsed or not.
+func (m Model) computeVisible() []int {
+	visible := []int{}
+	if m.isFiltering() || m.onlyUnhealthy {
+		visible = m.computeFiltered()
+	} else {
+		for i := 0; i < len(m.data); i++ {
+			visible = append(visible, i)
+			if m.collapsed[m.data[i].ID] {
+				i = m.subtreeEnd(i) - 1
+			}
+		}
+	}
+
+	// Zoomed views only show the subtree of their root
+	if root := m.zoomRoot(); root >= 0 {
+		end := m.subtreeEnd(root)
+		visible = slices.DeleteFunc(visible, func(i int) bool { return i < root || i >= end })
+	}
+	return visible
+}
+
+func (m Model) isFiltering() bool {
+	return m.filtering && m.searchMode == searchModeFilter
+}
+
+func (m Model) computeFiltered() []int {
+	keep := make([]bool, len(m.data))
+	for i, v := range m.data {
+		if m.isFiltering() && !m.matches(v) || m.onlyUnhealthy && !v.Unhealthy {
+			continue
+		}
+		for p := i; p >= 0 && !keep[p]; p = m.parentOf(p) {
+			keep[p] = true
+		}
+	}
+
+	visible := []int{}
+	for i, k := range keep {
+		if k {
+			visible = append(visible, i)
+		}
+	}
+	return visible
+}
+
+// treePrefixes returns the `├─`/`└─` prefixes of each visible row, taking into
+// account only rows that are visible, so hidden siblings do not leave dangling
+// branches behind.
+func (m Model) treePrefixes(visible []int) []string {
+	prefixes := make([]string, len(visible))
+	// open[d] reports if there is a row at depth d further down, before any shallower row
+	open := map[int]bool{}
+	base := m.baseDepth()
+
+	for k := len(visible) - 1; k >= 0; k-- {
+		depth := m.data[visible[k]].Depth - base
+		if depth > 0 {
+			var prefix string
+			for d := 1; d < depth; d++ {
+				if open[d] {
+					prefix += "│  "
+				} else {
+					prefix += "   "
+				}
+			}
+			if open[depth] {
+				prefix += "├─ "
+			} else {
+				prefix += "└─ "
+			}
+			prefixes[k] = prefix
+		}
+
+		open[depth] = true
+		for d := range open {
+			if d > depth {
+				delete(open, d)
+			}
+		}
+	}
+	return prefixes
+}
+
+// collapsedSummary describes what is hidden under a collapsed row, counting
+// all its descendants rather than only its children.
+func (m Model) collapsedSummary(i int) string {
+	end := m.subtreeEnd(i)
+	unhealthy := 0
+	for _, v := range m.data[i+1 : end] {
+		if v.Unhealthy {
+			unhealthy++
+		}
+	}
+
+	summary := fmt.Sprintf(" ▸ %d descendants", end-i-1)
+	if end-i-1 == 1 {
+		summary = " ▸ 1 descendant"
+	}
+	if unhealthy > 0 {
+		summary += fmt.Sprintf(", %d not ready", unhealthy)
+	}
+	return summary
+}
+
+func (m *Model) toggleCollapse(i int) {
+	if !m.hasChildren(i) {
+		return
+	}
+	id := m.data[i].ID
+	if m.collapsed[id] {
+		delete(m.collapsed, id)
+	} else {
+		m.collapsed[id] = true
+	}
+}
+
+// collapseToDepth shows all levels until depth, collapsing rows on it.
+func (m *Model) collapseToDepth(depth int) {
+	m.collapsed = map[string]bool{}
+	for i, v := range m.data {
+		if v.Depth == depth && m.hasChildren(i) {
+			m.collapsed[v.ID] = true
+		}
+	}
+}
+
+// expandUnhealthy only expands rows that have unhealthy descendants.
+func (m *Model) expandUnhealthy() {
+	m.collapsed = map[string]bool{}
+	for i, v := range m.data {
+		if !m.hasChildren(i) {
+			continue
+		}
+		unhealthy := false
+		for _, d := range m.data[i+1 : m.subtreeEnd(i)] {
+			unhealthy = unhealthy || d.Unhealthy
+		}
+		if !unhealthy {
+			m.collapsed[v.ID] = true
+		}
+	}
+}
+
+func (m Model) visibleDepth() int {
+	depth := 0
+	for _, i := range m.visible {
+		depth = max(depth, m.data[i].Depth)
+	}
+	return depth
+}
+
+func (m Model) maxDepth() int {
+	depth := 0
+	for _, v := range m.data {
+		depth = max(depth, v.Depth)
+	}
+	return depth
+}
@@ -0,0 +1,153 @@
+package navigator
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"testing"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/table"
+	"github.com/brunoluiz/xpdig/internal/xplane"
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+// subtreeSize is how many rows each of the subtrees repeated under the root of
+// the long fixture has: XObjectStorage (0), Bucket (1), User (2, not ready),
+// User (3, not synced), User (4, not ready), its User child (5, not ready) and
+// another User (6, synced unknown), with the Bucket and User (6) being siblings.
+const subtreeSize = 7
+
+// loadRows flattens the long fixture into rows with the name, synced and ready
+// columns. As names repeat, IDs are prefixed by the row position.
+func loadRows(t *testing.T) []DataRow {
+	t.Helper()
+	f, err := os.Open("../../../../fixture/crossplane-resource-long.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	trace, err := xplane.Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rows := []DataRow{}
+	var walk func(r *xplane.Resource, depth int)
+	walk = func(r *xplane.Resource, depth int) {
+		name := r.Unstructured.GetKind() + "/" + r.Unstructured.GetName()
+		s := xplane.GetResourceStatus(r, "")
+		rows = append(rows, DataRow{
+			ID:        fmt.Sprintf("%d:%s", len(rows), name),
+			Data:      len(rows),
+			Columns:   []string{name, s.Synced, s.Ready},
+			Depth:     depth,
+			Unhealthy: !s.Ok,
+		})
+		for _, c := range r.Children {
+			walk(c, depth+1)
+		}
+	}
+	walk(trace, 0)
+	return rows
+}
+
+// newTestModel returns a navigator showing the long fixture.
+func newTestModel(t *testing.T) Model {
+	t.Helper()
+	m := New(slog.New(slog.DiscardHandler), table.New(), textinput.New())
+	m.SetColumns([]table.Column{{Title: "NAME", Width: 40}, {Title: "SYNCED", Width: 8}, {Title: "READY", Width: 8}})
+	m.SetData(loadRows(t))
+	return m
+}
+
+// repeated returns the root position followed by the positions of every
+// repeated subtree, laid out by offsets within them.
+func repeated(rows int, offsets ...int) []int {
+	positions := []int{0}
+	for start := 1; start < rows; start += subtreeSize {
+		for _, o := range offsets {
+			positions = append(positions, start+o)
+		}
+	}
+	return positions
+}
+
+// positions returns where the rows were on the fixture.
+func positions(rows []DataRow, indexes []int) []int {
+	out := make([]int, 0, len(indexes))
+	for _, i := range indexes {
+		pos, _ := rows[i].Data.(int)
+		out = append(out, pos)
+	}
+	return out
+}
+
+func TestComputeVisible(t *testing.T) {
+	type args struct {
+		filter        string
+		onlyUnhealthy bool
+		collapseDepth int
+	}
+
+	type want struct {
+		offsets []int
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"All": {
+			reason: "Should show every row if nothing is collapsed or filtered",
+			args:   args{collapseDepth: -1},
+			want:   want{offsets: []int{0, 1, 2, 3, 4, 5, 6}},
+		},
+		"CollapseToDepth": {
+			reason: "Should hide the descendants of rows on the collapsed depth",
+			args:   args{collapseDepth: 2},
+			want:   want{offsets: []int{0, 1, 6}},
+		},
+		"Filter": {
+			reason: "Should only show matching rows and their ancestors",
+			args:   args{filter: "leaf-1-1", collapseDepth: -1},
+			want:   want{offsets: []int{0, 1, 4, 5}},
+		},
+		"FilterIgnoresCollapsed": {
+			reason: "Should show matching rows even if under collapsed ones",
+			args:   args{filter: "leaf-mid", collapseDepth: 1},
+			want:   want{offsets: []int{0, 1, 3}},
+		},
+		"OnlyUnhealthy": {
+			reason: "Should only show unhealthy rows and their healthy ancestors, even if under collapsed ones",
+			args:   args{onlyUnhealthy: true, collapseDepth: 1},
+			want:   want{offsets: []int{0, 1, 2, 3, 4, 5, 6}},
+		},
+		"FilterAndOnlyUnhealthy": {
+			reason: "Should only show rows both matching and unhealthy, and their ancestors",
+			args:   args{filter: "bucket-hash", onlyUnhealthy: true, collapseDepth: -1},
+			want:   want{offsets: []int{0, 1, 2, 3, 4, 5}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			m := newTestModel(t)
+			if tc.args.collapseDepth >= 0 {
+				m.collapseToDepth(tc.args.collapseDepth)
+			}
+			if tc.args.filter != "" {
+				if err := m.SetFilter(tc.args.filter); err != nil {
+					t.Fatal(err)
+				}
+			}
+			m.SetOnlyUnhealthy(tc.args.onlyUnhealthy)
+
+			want := repeated(len(m.data), tc.want.offsets...)
+			if got := positions(m.data, m.computeVisible()); !slices.Equal(want, got) {
+				t.Errorf("\n%s\ncomputeVisible(): want %v, got %v", tc.reason, want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package palette
+
+import (
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// EventClosed is sent when the palette is closed, either after running a
+// command or not.
+type EventClosed struct{}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.input.Width = max(msg.Width/2, 1)
+	case tea.KeyMsg:
+		cmd = m.onKey(msg)
+	}
+	return m, cmd
+}
+
+func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.KeyMap.Close):
+		m.input.Blur()
+		return closed
+	case key.Matches(msg, m.KeyMap.Run):
+		return m.onRun()
+	case key.Matches(msg, m.KeyMap.Complete):
+		m.onComplete()
+		return nil
+	case key.Matches(msg, m.KeyMap.HistoryPrevious):
+		m.onHistory(-1)
+		return nil
+	case key.Matches(msg, m.KeyMap.HistoryNext):
+		m.onHistory(1)
+		return nil
+	}
+
+	m.err = nil
+	m.completions = nil
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return cmd
+}
+
+func (m *Model) onRun() tea.Cmd {
+	line := strings.TrimSpace(m.input.Value())
+	if line == "" {
+		m.input.Blur()
+		return closed
+	}
+
+	words := strings.Fields(line)
+	c, ok := m.command(words[0])
+	if !ok {
+		m.err = &ErrUnknownCommand{Name: words[0]}
+		return nil
+	}
+
+	m.remember(line)
+	cmd, err := c.Run(words[1:])
+	if err != nil {
+		m.err = err
+		return nil
+	}
+
+	m.input.Blur()
+	return tea.Batch(closed, cmd)
+}
+
+// onComplete replaces the word being typed by the next suggestion, cycling
+// through them while tab is pressed.
+func (m *Model) onComplete() {
+	if m.completions == nil {
+		m.completionBase, m.completions = m.suggestions()
+		m.completion = 0
+	}
+	if len(m.completions) == 0 {
+		m.completions = nil
+		return
+	}
+
+	line := strings.Join(append(slices.Clone(m.completionBase), m.completions[m.completion]), " ")
+	if len(m.completions) == 1 {
+		// Nothing else to cycle through, so it is ready for the next word
+		line += " "
+		m.completions = nil
+	} else {
+		m.completion = (m.completion + 1) % len(m.completions)
+	}
+	m.input.SetValue(line)
+	m.input.CursorEnd()
+}
+
+func (m *Model) onHistory(dir int) {
+	pos := m.historyPos + dir
+	if pos < 0 || pos > len(m.history) {
+		return
+	}
+	if m.historyPos == len(m.history) {
+		m.draft = m.input.Value()
+	}
+
+	m.historyPos = pos
+	if pos == len(m.history) {
+		m.input.SetValue(m.draft)
+	} else {
+		m.input.SetValue(m.history[pos])
+	}
+	m.input.CursorEnd()
+	m.completions = nil
+	m.err = nil
+}
+
+// remember adds a line to the history, moving it to the end if already there.
+func (m *Model) remember(line string) {
+	m.history = slices.DeleteFunc(m.history, func(v string) bool { return v == line })
+	m.history = append(m.history, line)
+	if len(m.history) > maxHistory {
+		m.history = m.history[len(m.history)-maxHistory:]
+	}
+	m.historyPos = len(m.history)
+}
+
+func closed() tea.Msg { return EventClosed{} }
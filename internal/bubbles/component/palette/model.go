@@ -0,0 +1,148 @@
+// Package palette is a `:` command line, running commands from a registry
+// with autocompletion and history.
+package palette
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// maxHistory is how many commands are kept in the history.
+const maxHistory = 100
+
+// Command is an action which can be run from the palette.
+type Command struct {
+	Name string
+	// Usage describes the arguments, eg: `<namespace>`
+	Usage string
+	Help  string
+	// Complete returns the candidates for an argument, given the previous ones.
+	Complete func(args []string) []string
+	// Run executes the command, with errors being shown in the palette.
+	Run func(args []string) (tea.Cmd, error)
+}
+
+type ErrUnknownCommand struct {
+	Name string
+}
+
+func (e *ErrUnknownCommand) Error() string {
+	return fmt.Sprintf("unknown command '%s'", e.Name)
+}
+
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+
+	input    textinput.Model
+	commands []Command
+	width    int
+	err      error
+
+	history []string
+	// historyPos is the position in the history being shown, with
+	// len(history) being the line being typed
+	historyPos int
+	draft      string
+
+	// completions are cycled through while completing the current word
+	completions    []string
+	completion     int
+	completionBase []string
+}
+
+func New() Model {
+	input := textinput.New()
+	input.Prompt = ":"
+
+	return Model{
+		KeyMap: DefaultKeyMap(),
+		Styles: DefaultStyles(),
+		input:  input,
+	}
+}
+
+func (m Model) Init() tea.Cmd { return nil }
+
+// Register adds commands to the palette, replacing the ones with the same name.
+func (m *Model) Register(cmds ...Command) {
+	for _, c := range cmds {
+		m.commands = slices.DeleteFunc(m.commands, func(v Command) bool { return v.Name == c.Name })
+		m.commands = append(m.commands, c)
+	}
+	slices.SortFunc(m.commands, func(a, b Command) int { return strings.Compare(a.Name, b.Name) })
+}
+
+// Commands returns the registered commands, sorted by name.
+func (m Model) Commands() []Command { return m.commands }
+
+// Open focuses the palette with an empty line.
+func (m *Model) Open() tea.Cmd {
+	m.input.Reset()
+	m.err = nil
+	m.historyPos = len(m.history)
+	m.completions = nil
+	return m.input.Focus()
+}
+
+func (m Model) command(name string) (Command, bool) {
+	for _, c := range m.commands {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Command{}, false
+}
+
+// suggestions returns the candidates for the word being typed, besides
+// which words were typed before it.
+func (m Model) suggestions() ([]string, []string) {
+	words := strings.Fields(m.input.Value())
+	if len(words) == 0 || strings.HasSuffix(m.input.Value(), " ") {
+		words = append(words, "")
+	}
+	prev, current := words[:len(words)-1], words[len(words)-1]
+
+	candidates := []string{}
+	if len(prev) == 0 {
+		for _, c := range m.commands {
+			candidates = append(candidates, c.Name)
+		}
+	} else if c, ok := m.command(prev[0]); ok && c.Complete != nil {
+		candidates = c.Complete(prev[1:])
+	}
+
+	suggestions := []string{}
+	for _, c := range candidates {
+		if strings.HasPrefix(c, current) {
+			suggestions = append(suggestions, c)
+		}
+	}
+	return prev, suggestions
+}
+
+func (m Model) View() string {
+	line := m.input.View()
+	if m.err != nil {
+		return lipgloss.JoinHorizontal(lipgloss.Top, line, "  ", m.Styles.Error.Render("⚠ "+m.err.Error()))
+	}
+
+	hint := ""
+	if words := strings.Fields(m.input.Value()); len(words) > 0 {
+		if c, ok := m.command(words[0]); ok {
+			hint = strings.TrimSpace(c.Usage + "  " + c.Help)
+		}
+	}
+	if _, suggestions := m.suggestions(); len(suggestions) > 0 && m.input.Value() != "" {
+		hint = strings.Join(suggestions, " ")
+	}
+
+	width := max(m.width-lipgloss.Width(line)-2, 0)
+	return lipgloss.JoinHorizontal(lipgloss.Top, line, "  ", m.Styles.Hint.Render(ansi.Truncate(hint, width, "…")))
+}
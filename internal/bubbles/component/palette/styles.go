@@ -0,0 +1,15 @@
+package palette
+
+import "github.com/charmbracelet/lipgloss"
+
+type Styles struct {
+	Hint  lipgloss.Style
+	Error lipgloss.Style
+}
+
+func DefaultStyles() Styles {
+	return Styles{
+		Hint:  lipgloss.NewStyle().Foreground(lipgloss.Color("8")),
+		Error: lipgloss.NewStyle().Foreground(lipgloss.Color("9")),
+	}
+}
@@ -0,0 +1,59 @@
+package picker
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// EventClosed is sent when the modal is closed, either after choosing an item
+// or not.
+type EventClosed struct{}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.layout()
+	case tea.KeyMsg:
+		cmd = m.onKey(msg)
+	}
+	return m, cmd
+}
+
+func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.KeyMap.Up):
+		m.move(-1)
+	case key.Matches(msg, m.KeyMap.Down):
+		m.move(1)
+	case key.Matches(msg, m.KeyMap.Open):
+		return tea.Batch(closed, m.chosen(ActionOpen))
+	case key.Matches(msg, m.KeyMap.Describe):
+		return tea.Batch(closed, m.chosen(ActionDescribe))
+	case key.Matches(msg, m.KeyMap.OpenTab):
+		return tea.Batch(closed, m.chosen(ActionOpenTab))
+	case key.Matches(msg, m.KeyMap.Close):
+		return closed
+	}
+	return nil
+}
+
+func (m *Model) move(n int) {
+	if len(m.request.Items) == 0 {
+		return
+	}
+	m.cursor = min(max(m.cursor+n, 0), len(m.request.Items)-1)
+	m.layout()
+}
+
+func closed() tea.Msg { return EventClosed{} }
+
+func (m Model) chosen(a Action) tea.Cmd {
+	if m.request.OnChoose == nil || len(m.request.Items) == 0 {
+		return nil
+	}
+	onChoose, item := m.request.OnChoose, m.request.Items[m.cursor]
+	return func() tea.Msg { return onChoose(item, a) }
+}
@@ -0,0 +1,42 @@
+package picker
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Up       key.Binding
+	Down     key.Binding
+	Open     key.Binding
+	Describe key.Binding
+	OpenTab  key.Binding
+	Close    key.Binding
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Open: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "trace"),
+		),
+		Describe: key.NewBinding(
+			key.WithKeys("d"),
+			key.WithHelp("d", "describe"),
+		),
+		OpenTab: key.NewBinding(
+			key.WithKeys("t"),
+			key.WithHelp("t", "trace in new tab"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc", "close"),
+		),
+	}
+}
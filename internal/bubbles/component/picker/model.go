@@ -0,0 +1,124 @@
+// Package picker is a modal listing items to choose from, which can be opened,
+// described or opened in a new tab (eg: objects referenced by a resource).
+package picker
+
+import (
+	"strings"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/modal"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+)
+
+// Action is what is done with the chosen item.
+type Action int
+
+const (
+	ActionOpen Action = iota
+	ActionDescribe
+	ActionOpenTab
+)
+
+type Item struct {
+	Title       string
+	Description string
+	Data        any
+}
+
+// Request describes what is being chosen.
+type Request struct {
+	Title string
+	Items []Item
+	// OnChoose returns the message sent once an item is chosen.
+	OnChoose func(item Item, action Action) tea.Msg
+}
+
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Help   help.Model
+
+	request  Request
+	cursor   int
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+func New() Model {
+	return Model{
+		KeyMap:   DefaultKeyMap(),
+		Styles:   DefaultStyles(),
+		Help:     help.New(),
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (m Model) Init() tea.Cmd { return nil }
+
+// Ask replaces the items being chosen from.
+func (m *Model) Ask(r Request) {
+	m.request = r
+	m.cursor = 0
+	m.viewport.GotoTop()
+	m.layout()
+}
+
+func (m Model) frame() modal.Frame {
+	return modal.Frame{Box: m.Styles.Box, Margin: modal.Margin, Width: m.width, Height: m.height}
+}
+
+func (m *Model) layout() {
+	frame := m.frame()
+	width := frame.ContentWidth()
+	lines := make([]string, 0, len(m.request.Items))
+	for i, item := range m.request.Items {
+		line := item.Title
+		if item.Description != "" {
+			line += " " + m.Styles.Description.Render(item.Description)
+		}
+		line = ansi.Truncate(line, width, "…")
+		if i == m.cursor {
+			line = m.Styles.Selected.Render(ansi.Strip(line))
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		lines = append(lines, m.Styles.Description.Render("nothing to show"))
+	}
+
+	// Title, help and the blank lines around the list
+	chrome := 4
+	m.viewport.Width = width
+	m.viewport.Height = frame.ContentHeight(len(lines), chrome)
+	m.viewport.SetContent(strings.Join(lines, "\n"))
+
+	// Keep the cursor in sight
+	if m.cursor < m.viewport.YOffset {
+		m.viewport.SetYOffset(m.cursor)
+	} else if m.cursor >= m.viewport.YOffset+m.viewport.Height {
+		m.viewport.SetYOffset(m.cursor - m.viewport.Height + 1)
+	}
+}
+
+func (m Model) View() string {
+	return m.frame().View(
+		m.Styles.Title.Render(m.request.Title),
+		"",
+		m.viewport.View(),
+		"",
+		m.Styles.Help.Render(m.Help.ShortHelpView(m.ShortHelp())),
+	)
+}
+
+func (m Model) ShortHelp() []key.Binding {
+	k := m.KeyMap
+	return []key.Binding{k.Up, k.Down, k.Open, k.Describe, k.OpenTab, k.Close}
+}
+
+func (m Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
@@ -0,0 +1,41 @@
+package popup
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// EventCopied is sent when the popup text is meant to be copied.
+type EventCopied struct {
+	Text string
+}
+
+type EventClosed struct{}
+
+func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
+	var cmd tea.Cmd
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.layout()
+	case tea.KeyMsg:
+		cmd = m.onKey(msg)
+	}
+	return m, cmd
+}
+
+func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
+	switch {
+	case key.Matches(msg, m.KeyMap.Up):
+		m.viewport.ScrollUp(1)
+	case key.Matches(msg, m.KeyMap.Down):
+		m.viewport.ScrollDown(1)
+	case key.Matches(msg, m.KeyMap.Copy):
+		text := m.text
+		return func() tea.Msg { return EventCopied{Text: text} }
+	case key.Matches(msg, m.KeyMap.Close):
+		return func() tea.Msg { return EventClosed{} }
+	}
+	return nil
+}
@@ -0,0 +1,32 @@
+package popup
+
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	Up    key.Binding
+	Down  key.Binding
+	Copy  key.Binding
+	Close key.Binding
+}
+
+// DefaultKeyMap returns a default set of keybindings.
+func DefaultKeyMap() KeyMap {
+	return KeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "down"),
+		),
+		Copy: key.NewBinding(
+			key.WithKeys("c"),
+			key.WithHelp("c", "copy"),
+		),
+		Close: key.NewBinding(
+			key.WithKeys("esc", "enter", "q"),
+			key.WithHelp("esc", "close"),
+		),
+	}
+}
@@ -0,0 +1,80 @@
+// Package popup is a modal showing a scrollable text, which can be copied.
+package popup
+
+import (
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/modal"
+	"github.com/charmbracelet/bubbles/help"
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+type Model struct {
+	KeyMap KeyMap
+	Styles Styles
+	Help   help.Model
+
+	title    string
+	text     string
+	viewport viewport.Model
+	width    int
+	height   int
+}
+
+func New() Model {
+	return Model{
+		KeyMap:   DefaultKeyMap(),
+		Styles:   DefaultStyles(),
+		Help:     help.New(),
+		viewport: viewport.New(0, 0),
+	}
+}
+
+func (m Model) Init() tea.Cmd { return nil }
+
+// SetText sets the popup content, which gets wrapped to fit the popup.
+func (m *Model) SetText(title, text string) {
+	m.title = title
+	m.text = text
+	m.layout()
+	m.viewport.GotoTop()
+}
+
+func (m Model) Text() string { return m.text }
+
+func (m Model) frame() modal.Frame {
+	return modal.Frame{Box: m.Styles.Box, Margin: modal.Margin, Width: m.width, Height: m.height}
+}
+
+func (m *Model) layout() {
+	frame := m.frame()
+	width := frame.ContentWidth()
+	wrapped := ansi.Wrap(m.text, width, " ")
+
+	// Title, help and the blank lines around the text
+	chrome := 4
+	m.viewport.Width = width
+	m.viewport.Height = frame.ContentHeight(lipgloss.Height(wrapped), chrome)
+	m.viewport.SetContent(wrapped)
+}
+
+func (m Model) View() string {
+	return m.frame().View(
+		m.Styles.Title.Render(m.title),
+		"",
+		m.viewport.View(),
+		"",
+		m.Styles.Help.Render(m.Help.ShortHelpView(m.ShortHelp())),
+	)
+}
+
+func (m Model) ShortHelp() []key.Binding {
+	k := m.KeyMap
+	return []key.Binding{k.Up, k.Down, k.Copy, k.Close}
+}
+
+func (m Model) FullHelp() [][]key.Binding {
+	return [][]key.Binding{m.ShortHelp()}
+}
@@ -1,10 +1,20 @@
 package statusbar
 
 import (
+	"strings"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/action/shell"
 	"github.com/brunoluiz/xpdig/internal/bubbles/component/navigator"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/popup"
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+// EventToast shows a short lived message in the status bar.
+type EventToast struct {
+	Message string
+	Err     error
+}
+
 func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	m.statusbar.FourthColumn = ""
 	m.statusbar.FourthColumnColors = m.neutralColor
@@ -13,9 +23,13 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		cmd = m.onResize(msg)
-	case navigator.EventItemCopied:
+	case navigator.EventItemCopied, popup.EventCopied:
 		m.statusbar.FourthColumn = "copied"
 		m.statusbar.FourthColumnColors = m.secondaryColor
+	case EventToast:
+		m.onToast(msg)
+	case shell.EventRan:
+		m.onToast(EventToast{Message: lastLine(msg.Output), Err: msg.Err})
 	}
 
 	var statusbarCmd tea.Cmd
@@ -24,7 +38,23 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, tea.Batch(cmd, statusbarCmd)
 }
 
+func (m *Model) onToast(msg EventToast) {
+	if msg.Err != nil {
+		m.statusbar.FourthColumn = msg.Err.Error()
+		m.statusbar.FourthColumnColors = m.errorColor
+		return
+	}
+	m.statusbar.FourthColumn = msg.Message
+	m.statusbar.FourthColumnColors = m.secondaryColor
+}
+
 func (m *Model) onResize(msg tea.WindowSizeMsg) tea.Cmd {
 	m.statusbar.Width = msg.Width
 	return nil
 }
+
+// lastLine keeps toasts short, as commands might output one line per object.
+func lastLine(s string) string {
+	lines := strings.Split(s, "\n")
+	return lines[len(lines)-1]
+}
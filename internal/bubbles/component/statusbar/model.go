@@ -18,6 +18,7 @@ type Model struct {
 	primaryColor   statusbar.ColorConfig
 	secondaryColor statusbar.ColorConfig
 	neutralColor   statusbar.ColorConfig
+	errorColor     statusbar.ColorConfig
 }
 
 type config struct {
@@ -27,6 +28,7 @@ type config struct {
 	primaryColor   statusbar.ColorConfig
 	secondaryColor statusbar.ColorConfig
 	neutralColor   statusbar.ColorConfig
+	errorColor     statusbar.ColorConfig
 }
 
 type WithOpt func(*config)
@@ -43,6 +45,10 @@ func WithNeutralStatusColor(cl statusbar.ColorConfig) func(c *config) {
 	return func(c *config) { c.neutralColor = cl }
 }
 
+func WithErrorStatusColor(cl statusbar.ColorConfig) func(c *config) {
+	return func(c *config) { c.errorColor = cl }
+}
+
 func WithPathSeparator(p string) func(c *config) {
 	return func(c *config) { c.pathSeparator = p }
 }
@@ -71,6 +77,10 @@ func New(opts ...WithOpt) Model {
 			Foreground: lipgloss.AdaptiveColor{Dark: itoa(ansi.White), Light: itoa(ansi.White)},
 			Background: lipgloss.AdaptiveColor{Light: itoa(ansi.BrightBlack), Dark: itoa(ansi.BrightBlack)},
 		},
+		errorColor: statusbar.ColorConfig{
+			Foreground: lipgloss.AdaptiveColor{Dark: itoa(ansi.White), Light: itoa(ansi.White)},
+			Background: lipgloss.AdaptiveColor{Light: itoa(ansi.Red), Dark: itoa(ansi.Red)},
+		},
 	}
 	for _, opt := range opts {
 		opt(&cfg)
@@ -93,6 +103,7 @@ func New(opts ...WithOpt) Model {
 		primaryColor:   cfg.primaryColor,
 		secondaryColor: cfg.secondaryColor,
 		neutralColor:   cfg.neutralColor,
+		errorColor:     cfg.errorColor,
 	}
 }
 
@@ -101,6 +112,37 @@ func (m *Model) View() string { return m.statusbar.View() }
 
 func (m *Model) GetHeight() int { return statusbar.Height }
 
+// SetIndicators shows which view modes are enabled (eg: only unhealthy).
+func (m *Model) SetIndicators(indicators []string) {
+	m.statusbar.ThirdColumn = strings.Join(indicators, " ")
+	m.statusbar.ThirdColumnColors = m.neutralColor
+	if len(indicators) > 0 {
+		m.statusbar.ThirdColumnColors = m.secondaryColor
+	}
+}
+
+// PathSegmentAt returns which path segment is rendered at a given x position.
+func (m Model) PathSegmentAt(x int) (int, bool) {
+	// The path is in the second column, after the first one and its padding
+	first := lipgloss.NewStyle().Padding(0, 1).Render(m.statusbar.FirstColumn)
+	x -= lipgloss.Width(first) + 1
+
+	for i, segment := range m.path {
+		if x < 0 {
+			return 0, false
+		}
+		w := lipgloss.Width(segment)
+		if i < len(m.path)-1 {
+			w += lipgloss.Width(m.pathSeparator)
+		}
+		if x < w {
+			return i, true
+		}
+		x -= w
+	}
+	return 0, false
+}
+
 func (m *Model) SetPath(path []string) {
 	m.path = path
 	m.statusbar.SecondColumn = strings.Join(m.path, m.pathSeparator)
@@ -5,6 +5,13 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 )
 
+const (
+	// horizontalStep is how many columns are scrolled at a time.
+	horizontalStep = 10
+	// wheelStep is how many rows are scrolled by each mouse wheel event.
+	wheelStep = 3
+)
+
 // EventCursorUpdated N indicates how much the cursor moved
 type EventCursorUpdated struct {
 	Previous int
@@ -39,6 +46,21 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 			m.GotoTop()
 		case key.Matches(msg, m.KeyMap.GotoBottom):
 			m.GotoBottom()
+		case key.Matches(msg, m.KeyMap.ScrollLeft):
+			m.ScrollLeft(horizontalStep)
+		case key.Matches(msg, m.KeyMap.ScrollRight):
+			m.ScrollRight(horizontalStep)
+		}
+	case tea.MouseMsg:
+		switch msg.Button {
+		case tea.MouseButtonWheelUp:
+			m.MoveUp(wheelStep)
+		case tea.MouseButtonWheelDown:
+			m.MoveDown(wheelStep)
+		case tea.MouseButtonWheelLeft:
+			m.ScrollLeft(horizontalStep)
+		case tea.MouseButtonWheelRight:
+			m.ScrollRight(horizontalStep)
 		}
 	}
 
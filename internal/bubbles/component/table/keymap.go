@@ -13,6 +13,8 @@ type KeyMap struct {
 	HalfPageDown key.Binding
 	GotoTop      key.Binding
 	GotoBottom   key.Binding
+	ScrollLeft   key.Binding
+	ScrollRight  key.Binding
 }
 
 // DefaultKeyMap returns a default set of keybindings.
@@ -36,11 +38,11 @@ func DefaultKeyMap() KeyMap {
 		),
 		HalfPageUp: key.NewBinding(
 			key.WithKeys("ctrl+u"),
-			key.WithHelp("u", "½ page up"),
+			key.WithHelp("ctrl+u", "½ page up"),
 		),
 		HalfPageDown: key.NewBinding(
 			key.WithKeys("ctrl+d"),
-			key.WithHelp("d", "½ page down"),
+			key.WithHelp("ctrl+d", "½ page down"),
 		),
 		GotoTop: key.NewBinding(
 			key.WithKeys("home", "g"),
@@ -50,6 +52,14 @@ func DefaultKeyMap() KeyMap {
 			key.WithKeys("end", "G"),
 			key.WithHelp("G/end", "go to end"),
 		),
+		ScrollLeft: key.NewBinding(
+			key.WithKeys("left"),
+			key.WithHelp("←", "scroll left"),
+		),
+		ScrollRight: key.NewBinding(
+			key.WithKeys("right"),
+			key.WithHelp("→", "scroll right"),
+		),
 	}
 }
 
@@ -63,5 +73,6 @@ func (km KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{km.LineUp, km.LineDown, km.GotoTop, km.GotoBottom},
 		{km.PageUp, km.PageDown, km.HalfPageUp, km.HalfPageDown},
+		{km.ScrollLeft, km.ScrollRight},
 	}
 }
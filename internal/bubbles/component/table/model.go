@@ -6,6 +6,7 @@ import (
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 	"github.com/mattn/go-runewidth"
 )
 
@@ -23,14 +24,32 @@ type Model struct {
 	viewport viewport.Model
 	start    int
 	end      int
+
+	// xOffset scrolls the table horizontally, with the last column growing
+	// as it scrolls, so its truncated values can be read
+	xOffset int
+	// wrapColumn is the column wrapped across multiple lines in the selected
+	// row, with -1 disabling it
+	wrapColumn   int
+	cursorHeight int
 }
 
 // Row represents one line in the table.
 type Row []Cell
 
+// Cell represents one column of a row. Spans can be used to style parts of
+// the value differently, such as highlighting search matches.
 type Cell struct {
 	Value string
 	Style lipgloss.Style
+	Spans []Span
+}
+
+// Span styles runes from Start (inclusive) to End (exclusive) of a cell value.
+type Span struct {
+	Start int
+	End   int
+	Style lipgloss.Style
 }
 
 // Column defines the table structure.
@@ -47,8 +66,9 @@ type Option func(*Model)
 // New creates a new model for the table widget.
 func New(opts ...Option) Model {
 	m := Model{
-		cursor:   0,
-		viewport: viewport.New(0, 20), //nolint:mnd
+		cursor:     0,
+		viewport:   viewport.New(0, 20), //nolint:mnd
+		wrapColumn: -1,
 
 		KeyMap: DefaultKeyMap(),
 		Help:   help.New(),
@@ -134,7 +154,11 @@ func (m *Model) Blur() {
 
 // View renders the component.
 func (m Model) View() string {
-	return m.headersView() + "\n" + m.viewport.View()
+	headers := m.headersView()
+	if m.xOffset > 0 {
+		headers = ansi.Cut(headers, m.xOffset, m.xOffset+m.viewport.Width)
+	}
+	return headers + "\n" + m.viewport.View()
 }
 
 // HelpView is a helper method for rendering the help menu from the keymap.
@@ -158,13 +182,126 @@ func (m *Model) UpdateViewport() {
 		m.start = 0
 	}
 	m.end = clamp(m.cursor+m.viewport.Height, m.cursor, len(m.rows))
+	m.cursorHeight = 1
 	for i := m.start; i < m.end; i++ {
-		renderedRows = append(renderedRows, m.renderRow(i))
+		row := m.renderRow(i)
+		if i == m.cursor {
+			m.cursorHeight = lipgloss.Height(row)
+		}
+		renderedRows = append(renderedRows, row)
 	}
 
 	m.viewport.SetContent(
 		lipgloss.JoinVertical(lipgloss.Left, renderedRows...),
 	)
+	m.xOffset = clamp(m.xOffset, 0, m.maxXOffset())
+	m.viewport.SetXOffset(m.xOffset)
+	m.keepCursorVisible()
+}
+
+// keepCursorVisible scrolls the viewport, in case the selected row takes
+// multiple lines (see SetWrapColumn) and it is partially hidden.
+func (m *Model) keepCursorVisible() {
+	if m.cursorHeight <= 1 || m.cursor < m.start {
+		return
+	}
+
+	top := m.cursor - m.start
+	bottom := top + m.cursorHeight - 1
+	switch {
+	case bottom >= m.viewport.YOffset+m.viewport.Height:
+		m.viewport.SetYOffset(max(bottom-m.viewport.Height+1, 0))
+	case top < m.viewport.YOffset:
+		m.viewport.SetYOffset(top)
+	}
+}
+
+// maxXOffset returns how far the table can scroll horizontally, which is
+// until the longest value of the last column is fully visible.
+func (m Model) maxXOffset() int {
+	if len(m.cols) == 0 {
+		return 0
+	}
+
+	last := len(m.cols) - 1
+	longest := 0
+	for _, row := range m.rows {
+		if last < len(row) {
+			longest = max(longest, runewidth.StringWidth(row[last].Value))
+		}
+	}
+	return max(longest-m.cols[last].Width, 0)
+}
+
+// ScrollLeft scrolls the table to the left by n columns.
+func (m *Model) ScrollLeft(n int) {
+	m.xOffset = max(m.xOffset-n, 0)
+	m.UpdateViewport()
+}
+
+// ScrollRight scrolls the table to the right by n columns.
+func (m *Model) ScrollRight(n int) {
+	m.xOffset = min(m.xOffset+n, m.maxXOffset())
+	m.UpdateViewport()
+}
+
+// RowAt returns the row rendered at a line of the table, with line 0 being
+// the headers.
+func (m Model) RowAt(y int) (int, bool) {
+	line := y - lipgloss.Height(m.headersView())
+	if line < 0 || line >= m.viewport.Height {
+		return 0, false
+	}
+
+	line += m.viewport.YOffset
+	for i := m.start; i < m.end; i++ {
+		height := 1
+		if i == m.cursor {
+			height = m.cursorHeight
+		}
+		if line < height {
+			return i, true
+		}
+		line -= height
+	}
+	return 0, false
+}
+
+// ColumnAt returns the column rendered at a given x position.
+func (m Model) ColumnAt(x int) (int, bool) {
+	x += m.xOffset
+	for i, col := range m.cols {
+		if col.Width <= 0 {
+			continue
+		}
+		w := m.colWidth(i) + m.styles.Header.GetHorizontalFrameSize()
+		if x < w {
+			return i, true
+		}
+		x -= w
+	}
+	return 0, false
+}
+
+// XOffset returns how far the table is scrolled horizontally.
+func (m Model) XOffset() int {
+	return m.xOffset
+}
+
+// SetWrapColumn sets which column is wrapped across multiple lines in the
+// selected row, instead of being truncated. Use -1 to disable it.
+func (m *Model) SetWrapColumn(col int) {
+	m.wrapColumn = col
+	m.UpdateViewport()
+}
+
+// colWidth returns the width of a column, taking into account that the last
+// one grows while scrolling horizontally.
+func (m Model) colWidth(i int) int {
+	if i == len(m.cols)-1 && m.cols[i].Width > 0 {
+		return m.cols[i].Width + m.xOffset
+	}
+	return m.cols[i].Width
 }
 
 // SelectedRow returns the selected row.
@@ -255,12 +392,13 @@ func (m *Model) FromValues(value, separator string) {
 
 func (m Model) headersView() string {
 	s := make([]string, 0, len(m.cols))
-	for _, col := range m.cols {
+	for i, col := range m.cols {
 		if col.Width <= 0 {
 			continue
 		}
-		style := lipgloss.NewStyle().Width(col.Width).MaxWidth(col.Width).Inline(true)
-		renderedCell := style.Render(runewidth.Truncate(col.Title, col.Width, "…"))
+		width := m.colWidth(i)
+		style := lipgloss.NewStyle().Width(width).MaxWidth(width).Inline(true)
+		renderedCell := style.Render(runewidth.Truncate(col.Title, width, "…"))
 		s = append(s, m.styles.Header.Render(renderedCell))
 	}
 	return lipgloss.JoinHorizontal(lipgloss.Top, s...)
@@ -269,16 +407,37 @@ func (m Model) headersView() string {
 func (m *Model) renderRow(r int) string {
 	s := make([]string, 0, len(m.cols))
 	for i, c := range m.rows[r] {
+		// Rows might be outdated for a moment, while columns are changed
+		if i >= len(m.cols) {
+			break
+		}
 		if m.cols[i].Width <= 0 {
 			continue
 		}
-		style := lipgloss.NewStyle().Width(m.cols[i].Width).MaxWidth(m.cols[i].Width).Inline(true)
+		width := m.colWidth(i)
+		style := lipgloss.NewStyle().Width(width).MaxWidth(width).Inline(true)
 		renderedCell := m.styles.Cell
 		if r != m.cursor {
 			renderedCell = renderedCell.Inherit(c.Style)
 		}
 
-		s = append(s, renderedCell.Render(style.Render(runewidth.Truncate(c.Value, m.cols[i].Width, "…"))))
+		if r == m.cursor && i == m.wrapColumn {
+			wrapped := lipgloss.NewStyle().Width(width).Render(ansi.Wordwrap(c.Value, width, " "))
+			s = append(s, renderedCell.Render(wrapped))
+			continue
+		}
+
+		value := runewidth.Truncate(c.Value, width, "…")
+		if len(c.Spans) > 0 {
+			base := c.Style
+			if r == m.cursor {
+				base = m.styles.Selected
+			}
+			renderedCell = m.styles.Cell.Inherit(base)
+			value = renderSpans(value, c.Spans, base)
+		}
+
+		s = append(s, renderedCell.Render(style.Render(value)))
 	}
 
 	row := lipgloss.JoinHorizontal(lipgloss.Top, s...)
@@ -290,6 +449,34 @@ func (m *Model) renderRow(r int) string {
 	return row
 }
 
+// renderSpans renders each part of the value with its own style, so span
+// styles do not reset the base style of the remaining text.
+func renderSpans(value string, spans []Span, base lipgloss.Style) string {
+	runes := []rune(value)
+	styles := make([]*lipgloss.Style, len(runes))
+	for _, sp := range spans {
+		st := sp.Style.Inherit(base)
+		for i := max(sp.Start, 0); i < min(sp.End, len(runes)); i++ {
+			styles[i] = &st
+		}
+	}
+
+	b := strings.Builder{}
+	for start := 0; start < len(runes); {
+		end := start + 1
+		for end < len(runes) && styles[end] == styles[start] {
+			end++
+		}
+		st := base
+		if styles[start] != nil {
+			st = *styles[start]
+		}
+		b.WriteString(st.Render(string(runes[start:end])))
+		start = end
+	}
+	return b.String()
+}
+
 func clamp(v, low, high int) int {
 	return min(max(v, low), high)
 }
@@ -0,0 +1,136 @@
+// Package keys remaps the key bindings of components, which are structs of
+// key.Binding (eg: navigator.KeyMap), and detects conflicts between them.
+package keys
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// ErrUnknownAction is returned when remapping a binding which does not exist.
+type ErrUnknownAction struct {
+	Scope  string
+	Action string
+}
+
+func (e *ErrUnknownAction) Error() string {
+	return fmt.Sprintf("unknown key binding '%s.%s'", e.Scope, e.Action)
+}
+
+// ErrConflict is returned when the same key is bound to more than one action.
+type ErrConflict struct {
+	Key     string
+	Actions []string
+}
+
+func (e *ErrConflict) Error() string {
+	return fmt.Sprintf("key '%s' is bound to more than one action: %s", e.Key, strings.Join(e.Actions, ", "))
+}
+
+// Action is a binding of a keymap, named as `scope.action`.
+type Action struct {
+	Name    string
+	Binding key.Binding
+}
+
+// Remap replaces the keys of the bindings of keymap, which must be a pointer
+// to a struct of key.Binding. Actions are the field names in camel case (eg:
+// `searchNext`), and the help shows the new keys. No keys unbind an action.
+func Remap(scope string, keymap any, actions map[string][]string) error {
+	v := reflect.ValueOf(keymap).Elem()
+	for _, action := range slices.Sorted(maps.Keys(actions)) {
+		f := v.FieldByNameFunc(func(name string) bool { return actionName(name) == action })
+		if !f.IsValid() || f.Type() != reflect.TypeOf(key.Binding{}) {
+			return &ErrUnknownAction{Scope: scope, Action: action}
+		}
+
+		b := f.Addr().Interface().(*key.Binding)
+		b.SetKeys(actions[action]...)
+		b.SetHelp(helpKeys(actions[action]), b.Help().Desc)
+		b.SetEnabled(len(actions[action]) > 0)
+	}
+	return nil
+}
+
+// Actions returns the bindings of keymap, which must be a struct of
+// key.Binding, named as `scope.action`.
+func Actions(scope string, keymap any) []Action {
+	v := reflect.ValueOf(keymap)
+	actions := []Action{}
+	for i := range v.NumField() {
+		b, ok := v.Field(i).Interface().(key.Binding)
+		if !ok {
+			continue
+		}
+		actions = append(actions, Action{Name: scope + "." + actionName(v.Type().Field(i).Name), Binding: b})
+	}
+	return actions
+}
+
+// Conflicts returns an error if the same key is bound to more than one of
+// the actions, which should be the ones active at the same time.
+func Conflicts(actions []Action) error {
+	byKey := map[string][]string{}
+	for _, a := range actions {
+		if !a.Binding.Enabled() {
+			continue
+		}
+		for _, k := range a.Binding.Keys() {
+			if !slices.Contains(byKey[k], a.Name) {
+				byKey[k] = append(byKey[k], a.Name)
+			}
+		}
+	}
+
+	for _, k := range slices.Sorted(maps.Keys(byKey)) {
+		names := byKey[k]
+		if len(names) > 1 {
+			return &ErrConflict{Key: k, Actions: names}
+		}
+	}
+	return nil
+}
+
+// Without returns a copy of b without the keys bound by any of the actions,
+// with the help showing the remaining keys.
+func Without(b key.Binding, actions []Action) key.Binding {
+	keys := slices.DeleteFunc(slices.Clone(b.Keys()), func(k string) bool {
+		return slices.ContainsFunc(actions, func(a Action) bool {
+			return a.Binding.Enabled() && slices.Contains(a.Binding.Keys(), k)
+		})
+	})
+	help := b.Help().Key
+	if len(keys) < len(b.Keys()) {
+		help = helpKeys(keys)
+	}
+	without := key.NewBinding(key.WithKeys(keys...), key.WithHelp(help, b.Help().Desc))
+	without.SetEnabled(len(keys) > 0)
+	return without
+}
+
+// actionName converts a field name into an action name (eg: SearchNext
+// into searchNext).
+func actionName(field string) string {
+	r := []rune(field)
+	r[0] = unicode.ToLower(r[0])
+	return string(r)
+}
+
+// helpKeys describes keys the way they are shown in the help.
+func helpKeys(keys []string) string {
+	names := map[string]string{"up": "↑", "down": "↓", "left": "←", "right": "→", " ": "space"}
+	help := []string{}
+	for _, k := range keys {
+		if n, ok := names[k]; ok {
+			k = n
+		}
+		help = append(help, k)
+	}
+	return strings.Join(help, "/")
+}
@@ -0,0 +1,134 @@
+package keys
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+)
+
+type keyMap struct {
+	Up         key.Binding
+	SearchNext key.Binding
+}
+
+func defaultKeyMap() keyMap {
+	return keyMap{
+		Up:         key.NewBinding(key.WithKeys("up", "k"), key.WithHelp("↑/k", "up")),
+		SearchNext: key.NewBinding(key.WithKeys("n"), key.WithHelp("n", "next")),
+	}
+}
+
+func TestRemap(t *testing.T) {
+	type args struct {
+		actions map[string][]string
+	}
+
+	type want struct {
+		keys []string
+		help string
+		err  bool
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Remap": {
+			reason: "Should replace the keys and show them in the help",
+			args:   args{actions: map[string][]string{"searchNext": {"down", "ctrl+n"}}},
+			want:   want{keys: []string{"down", "ctrl+n"}, help: "↓/ctrl+n"},
+		},
+		"Unbind": {
+			reason: "Should disable actions without keys",
+			args:   args{actions: map[string][]string{"searchNext": {}}},
+			want:   want{},
+		},
+		"UnknownAction": {
+			reason: "Should fail on actions which are not in the keymap",
+			args:   args{actions: map[string][]string{"SearchNext": {"x"}}},
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			km := defaultKeyMap()
+			err := Remap("test", &km, tc.args.actions)
+			if tc.want.err {
+				var actionErr *ErrUnknownAction
+				if !errors.As(err, &actionErr) {
+					t.Fatalf("\n%s\nRemap(): want ErrUnknownAction, got %v", tc.reason, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nRemap(): unexpected error: %v", tc.reason, err)
+			}
+			if len(tc.want.keys) == 0 {
+				if km.SearchNext.Enabled() {
+					t.Errorf("\n%s\nRemap(): want binding disabled", tc.reason)
+				}
+				return
+			}
+			if !slices.Equal(tc.want.keys, km.SearchNext.Keys()) {
+				t.Errorf("\n%s\nRemap(): want keys %v, got %v", tc.reason, tc.want.keys, km.SearchNext.Keys())
+			}
+			if tc.want.help != km.SearchNext.Help().Key {
+				t.Errorf("\n%s\nRemap(): want help %q, got %q", tc.reason, tc.want.help, km.SearchNext.Help().Key)
+			}
+		})
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	type args struct {
+		actions map[string][]string
+	}
+
+	type want struct {
+		key string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoConflict": {
+			reason: "Should accept the default keys",
+			args:   args{},
+			want:   want{},
+		},
+		"Conflict": {
+			reason: "Should point out keys bound to more than one action",
+			args:   args{actions: map[string][]string{"searchNext": {"k"}}},
+			want:   want{key: "k"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			km := defaultKeyMap()
+			if err := Remap("test", &km, tc.args.actions); err != nil {
+				t.Fatalf("\n%s\nRemap(): unexpected error: %v", tc.reason, err)
+			}
+
+			err := Conflicts(Actions("test", km))
+			if tc.want.key == "" {
+				if err != nil {
+					t.Fatalf("\n%s\nConflicts(): unexpected error: %v", tc.reason, err)
+				}
+				return
+			}
+
+			var conflictErr *ErrConflict
+			if !errors.As(err, &conflictErr) {
+				t.Fatalf("\n%s\nConflicts(): want ErrConflict, got %v", tc.reason, err)
+			}
+			if conflictErr.Key != tc.want.key {
+				t.Errorf("\n%s\nConflicts(): want key %q, got %q", tc.reason, tc.want.key, conflictErr.Key)
+			}
+		})
+	}
+}
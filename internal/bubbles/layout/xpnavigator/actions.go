@@ -0,0 +1,96 @@
+package xpnavigator
+
+import (
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/palette"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/statusbar"
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// eventAction runs an action from the palette, as if its key was pressed.
+type eventAction struct {
+	name string
+}
+
+// action is done by the layout itself, while the ones against items are
+// done by the navigator (see navigator.Actions).
+type action struct {
+	name    string
+	help    string
+	binding key.Binding
+	run     func(m *Model) tea.Cmd
+}
+
+func (m Model) actions() []action {
+	return []action{
+		{
+			name:    "refs",
+			help:    "list the objects referenced by the resource",
+			binding: m.keyMap.References,
+			run:     (*Model).onShowReferences,
+		},
+		{
+			name:    "back",
+			help:    "trace the previous object",
+			binding: m.keyMap.Back,
+			run:     func(m *Model) tea.Cmd { return m.onHistory(historyBack) },
+		},
+		{
+			name:    "forward",
+			help:    "trace the next object",
+			binding: m.keyMap.Forward,
+			run:     func(m *Model) tea.Cmd { return m.onHistory(historyForward) },
+		},
+		{
+			name:    "message",
+			help:    "show the full status message of the resource",
+			binding: m.keyMap.ShowMessage,
+			run:     (*Model).onShowMessage,
+		},
+	}
+}
+
+// actionCommands plugs every action into the palette, eg: `:describe`.
+func (m Model) actionCommands() []palette.Command {
+	cmds := []palette.Command{}
+	for _, a := range m.navigator.Actions() {
+		cmds = append(cmds, actionCommand(a.Name, a.Help))
+	}
+	for _, a := range m.actions() {
+		cmds = append(cmds, actionCommand(a.name, a.help))
+	}
+	return cmds
+}
+
+func actionCommand(name, help string) palette.Command {
+	return palette.Command{
+		Name: name,
+		Help: help,
+		Run: func(args []string) (tea.Cmd, error) {
+			if len(args) > 0 {
+				return nil, &ErrInvalidArgs{Usage: name}
+			}
+			return func() tea.Msg { return eventAction{name: name} }, nil
+		},
+	}
+}
+
+// onAction runs an action by its name, unless it changes resources while in
+// read-only mode.
+func (m *Model) onAction(name string) tea.Cmd {
+	for _, a := range m.actions() {
+		if a.name == name {
+			return a.run(m)
+		}
+	}
+	for _, a := range m.navigator.Actions() {
+		if a.Name != name {
+			continue
+		}
+		if a.Writes && m.isReadOnly() {
+			return func() tea.Msg { return statusbar.EventToast{Err: ErrReadOnly} }
+		}
+		return m.navigator.RunAction(name)
+	}
+	return nil
+}
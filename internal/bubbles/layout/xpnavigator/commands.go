@@ -0,0 +1,396 @@
+package xpnavigator
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/navigator"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/palette"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/statusbar"
+	"github.com/brunoluiz/xpdig/internal/xplane"
+	"github.com/brunoluiz/xpdig/internal/xplane/export"
+	"github.com/brunoluiz/xpdig/internal/xplane/query"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Target is what is being traced.
+type Target struct {
+	Namespace string
+	Context   string
+	Kind      string
+	Object    string
+}
+
+func (t Target) String() string { return t.Kind + "/" + t.Object }
+
+// ErrRetargetUnavailable is returned when changing what is traced, while not
+// tracing live resources (eg: reading from stdin).
+var ErrRetargetUnavailable = errors.New("only available when tracing live resources")
+
+type ErrInvalidArgs struct {
+	Usage string
+}
+
+func (e *ErrInvalidArgs) Error() string {
+	return fmt.Sprintf("usage: %s", e.Usage)
+}
+
+// EventOpenTab is sent when a target is meant to be traced in a new tab.
+type EventOpenTab struct {
+	Target Target
+}
+
+// EventCloseTab is sent when the current tab is meant to be closed.
+type EventCloseTab struct{}
+
+// EventTargetChanged is sent once the trace of another target is loaded (eg:
+// after `:ctx`), so other components can act against it as well.
+type EventTargetChanged struct {
+	Target Target
+}
+
+type eventRetarget struct {
+	target Target
+	move   historyMove
+}
+
+type eventRetargeted struct {
+	target Target
+	move   historyMove
+	tracer Tracer
+	trace  *xplane.Resource
+	err    error
+}
+
+type eventFilter struct {
+	query string
+}
+
+type eventExport struct {
+	format export.Format
+	dst    string
+}
+
+type eventColumns struct {
+	mode string
+}
+
+const (
+	columnsShort  = "short"
+	columnsWide   = "wide"
+	columnsAuto   = "auto"
+	columnsManage = "manage"
+)
+
+// filterKeys are suggested while typing a `:filter` query.
+var filterKeys = []string{
+	"kind:", "name:", "ns:", "group:", "label:", "status~",
+	"ready=", "synced=", "healthy=", "installed=",
+	"paused", "deleting", "unhealthy", "error", "AND", "OR", "NOT",
+}
+
+// commands returns the palette commands, with completions based on the
+// current trace. They only send messages, as the model is copied around.
+func (m Model) commands() []palette.Command {
+	namespaces := map[string]bool{}
+	objects := []string{}
+	for id, row := range m.rowsByID() {
+		objects = append(objects, id)
+		if ns := row.Unstructured.GetNamespace(); ns != "" {
+			namespaces[ns] = true
+		}
+	}
+	slices.Sort(objects)
+
+	sortedNamespaces := slices.Sorted(maps.Keys(namespaces))
+	cmds := []palette.Command{
+		m.nsCommand(sortedNamespaces),
+		m.ctxCommand(),
+		m.traceCommand(objects),
+		filterCommand(),
+		exportCommand(),
+		columnsCommand(),
+	}
+	cmds = append(cmds, m.tabCommands(objects, sortedNamespaces)...)
+	cmds = append(cmds, quitCommand())
+	return append(cmds, m.actionCommands()...)
+}
+
+// retargetCommand sends a message to trace another target, if possible.
+func (m Model) retargetCommand(t Target) (tea.Cmd, error) {
+	if m.retarget == nil {
+		return nil, ErrRetargetUnavailable
+	}
+	return func() tea.Msg { return eventRetarget{target: t} }, nil
+}
+
+func (m Model) nsCommand(namespaces []string) palette.Command {
+	return palette.Command{
+		Name:  "ns",
+		Usage: "<namespace>",
+		Help:  "trace the same object in another namespace",
+		Complete: func(args []string) []string {
+			if len(args) > 0 {
+				return nil
+			}
+			return namespaces
+		},
+		Run: func(args []string) (tea.Cmd, error) {
+			if len(args) != 1 {
+				return nil, &ErrInvalidArgs{Usage: "ns <namespace>"}
+			}
+			t := m.target
+			t.Namespace = args[0]
+			return m.retargetCommand(t)
+		},
+	}
+}
+
+func (m Model) ctxCommand() palette.Command {
+	return palette.Command{
+		Name:  "ctx",
+		Usage: "<context>",
+		Help:  "trace the same object in another Kubernetes context",
+		Complete: func(args []string) []string {
+			if len(args) > 0 {
+				return nil
+			}
+			return m.contexts
+		},
+		Run: func(args []string) (tea.Cmd, error) {
+			if len(args) != 1 {
+				return nil, &ErrInvalidArgs{Usage: "ctx <context>"}
+			}
+			t := m.target
+			t.Context = args[0]
+			return m.retargetCommand(t)
+		},
+	}
+}
+
+func (m Model) traceCommand(objects []string) palette.Command {
+	return palette.Command{
+		Name:  "trace",
+		Usage: "<Kind/name>",
+		Help:  "trace another object",
+		Complete: func(args []string) []string {
+			if len(args) > 0 {
+				return nil
+			}
+			return objects
+		},
+		Run: func(args []string) (tea.Cmd, error) {
+			if len(args) != 1 {
+				return nil, &ErrInvalidArgs{Usage: "trace <Kind/name>"}
+			}
+			kind, object, ok := strings.Cut(args[0], "/")
+			if !ok || kind == "" || object == "" {
+				return nil, &ErrInvalidArgs{Usage: "trace <Kind/name>"}
+			}
+			t := m.target
+			t.Kind, t.Object = kind, object
+			return m.retargetCommand(t)
+		},
+	}
+}
+
+func filterCommand() palette.Command {
+	return palette.Command{
+		Name:  "filter",
+		Usage: "[query]",
+		Help:  "filter resources, or clear the filter if no query is given",
+		Complete: func([]string) []string {
+			return filterKeys
+		},
+		Run: func(args []string) (tea.Cmd, error) {
+			q := strings.Join(args, " ")
+			if q != "" {
+				if _, err := query.Parse(q); err != nil {
+					return nil, err
+				}
+			}
+			return func() tea.Msg { return eventFilter{query: q} }, nil
+		},
+	}
+}
+
+func exportCommand() palette.Command {
+	formats := []export.Format{export.FormatDOT, export.FormatMermaid, export.FormatHTML}
+	return palette.Command{
+		Name:  "export",
+		Usage: "<dot|mermaid|html> [file]",
+		Help:  "export the trace, into the current directory by default",
+		Complete: func(args []string) []string {
+			if len(args) > 0 {
+				return nil
+			}
+			return []string{string(export.FormatDOT), string(export.FormatMermaid), string(export.FormatHTML)}
+		},
+		Run: func(args []string) (tea.Cmd, error) {
+			if len(args) < 1 || len(args) > 2 {
+				return nil, &ErrInvalidArgs{Usage: "export <dot|mermaid|html> [file]"}
+			}
+			f := export.Format(args[0])
+			if !slices.Contains(formats, f) {
+				return nil, &export.ErrUnknownFormat{Format: args[0]}
+			}
+			e := eventExport{format: f}
+			if len(args) == 2 {
+				e.dst = args[1]
+			}
+			return func() tea.Msg { return e }, nil
+		},
+	}
+}
+
+func columnsCommand() palette.Command {
+	return palette.Command{
+		Name:  "columns",
+		Usage: "<short|wide|auto|manage>",
+		Help:  "switch the columns layout, or manage its columns",
+		Complete: func(args []string) []string {
+			if len(args) > 0 {
+				return nil
+			}
+			return []string{columnsAuto, columnsManage, columnsShort, columnsWide}
+		},
+		Run: func(args []string) (tea.Cmd, error) {
+			if len(args) != 1 || !slices.Contains([]string{columnsShort, columnsWide, columnsAuto, columnsManage}, args[0]) {
+				return nil, &ErrInvalidArgs{Usage: "columns <short|wide|auto|manage>"}
+			}
+			return func() tea.Msg { return eventColumns{mode: args[0]} }, nil
+		},
+	}
+}
+
+// tabCommands open and close tabs, each tracing its own object.
+func (m Model) tabCommands(objects, namespaces []string) []palette.Command {
+	return []palette.Command{
+		{
+			Name:  "tabnew",
+			Usage: "<Kind/name> [namespace]",
+			Help:  "trace another object in a new tab",
+			Complete: func(args []string) []string {
+				switch len(args) {
+				case 0:
+					return objects
+				case 1:
+					return namespaces
+				}
+				return nil
+			},
+			Run: func(args []string) (tea.Cmd, error) {
+				usage := &ErrInvalidArgs{Usage: "tabnew <Kind/name> [namespace]"}
+				if len(args) < 1 || len(args) > 2 {
+					return nil, usage
+				}
+				kind, object, ok := strings.Cut(args[0], "/")
+				if !ok || kind == "" || object == "" {
+					return nil, usage
+				}
+				t := m.target
+				t.Kind, t.Object = kind, object
+				if len(args) == 2 {
+					t.Namespace = args[1]
+				}
+				return func() tea.Msg { return EventOpenTab{Target: t} }, nil
+			},
+		},
+		{
+			Name: "tabclose",
+			Help: "close the current tab",
+			Run: func([]string) (tea.Cmd, error) {
+				return func() tea.Msg { return EventCloseTab{} }, nil
+			},
+		},
+	}
+}
+
+func quitCommand() palette.Command {
+	return palette.Command{
+		Name: "quit",
+		Help: "quit",
+		Run: func([]string) (tea.Cmd, error) {
+			return func() tea.Msg { return navigator.EventQuitted{} }, nil
+		},
+	}
+}
+
+// rowsByID returns the resources of the current trace by their row ID.
+func (m Model) rowsByID() map[string]*xplane.Resource {
+	rows := map[string]*xplane.Resource{}
+	var walk func(r *xplane.Resource)
+	walk = func(r *xplane.Resource) {
+		gvk := r.Unstructured.GroupVersionKind()
+		rows[fmt.Sprintf("%s.%s/%s", gvk.Kind, gvk.Group, r.Unstructured.GetName())] = r
+		for _, c := range r.Children {
+			walk(c)
+		}
+	}
+	if m.trace != nil {
+		walk(m.trace)
+	}
+	return rows
+}
+
+func (m *Model) onCommand() tea.Cmd {
+	m.palette.Register(m.commands()...)
+	m.usingPalette = true
+	return m.palette.Open()
+}
+
+// onRetarget loads the trace of another target, only switching to it once
+// loaded, so typos do not leave the user without a trace.
+func (m *Model) onRetarget(msg eventRetarget) tea.Cmd {
+	tracer := m.retarget(msg.target)
+	return tea.Batch(
+		func() tea.Msg { return statusbar.EventToast{Message: "loading " + msg.target.String() + "…"} },
+		func() tea.Msg {
+			trace, err := tracer.GetTrace()
+			return eventRetargeted{target: msg.target, move: msg.move, tracer: tracer, trace: trace, err: err}
+		},
+	)
+}
+
+func (m *Model) onRetargeted(msg eventRetargeted) tea.Cmd {
+	if msg.err != nil {
+		return func() tea.Msg { return statusbar.EventToast{Err: msg.err} }
+	}
+
+	m.record(msg.move)
+
+	// Watch ticks of the previous target are ignored from now on
+	m.generation++
+	m.target = msg.target
+	m.tracer = msg.tracer
+	m.pathByData = map[string][]string{}
+	target := msg.target
+	return tea.Batch(
+		m.onCrossplaneUpdate(msg.trace),
+		func() tea.Msg { return EventTargetChanged{Target: target} },
+	)
+}
+
+func (m *Model) onFilter(msg eventFilter) tea.Cmd {
+	if err := m.navigator.SetFilter(msg.query); err != nil {
+		return func() tea.Msg { return statusbar.EventToast{Err: err} }
+	}
+	return nil
+}
+
+func (m *Model) onColumns(msg eventColumns) tea.Cmd {
+	switch msg.mode {
+	case columnsManage:
+		return m.onManageColumns()
+	case columnsAuto:
+		m.shortPinned = false
+	default:
+		m.shortPinned = true
+		m.short = msg.mode == columnsShort
+	}
+	m.refreshColumns()
+	return nil
+}
@@ -1,10 +1,24 @@
 package xpnavigator
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/columnmanager"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/confirm"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/keyhelp"
 	"github.com/brunoluiz/xpdig/internal/bubbles/component/navigator"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/palette"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/picker"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/popup"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/statusbar"
 	"github.com/brunoluiz/xpdig/internal/xplane"
+	"github.com/brunoluiz/xpdig/internal/xplane/export"
+	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -14,12 +28,75 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case *xplane.Resource:
 		cmd = m.onCrossplaneUpdate(msg)
+	case eventTraced:
+		return m, m.onTraced(msg)
+	case eventWatchTick:
+		return m, m.onWatchTick(msg)
+	case eventRetarget:
+		return m, m.onRetarget(msg)
+	case eventRetargeted:
+		cmd = m.onRetargeted(msg)
+	case eventFilter:
+		cmd = m.onFilter(msg)
+	case eventExport:
+		return m, m.export(msg.format, msg.dst)
+	case eventColumns:
+		cmd = m.onColumns(msg)
+	case eventAction:
+		cmd = m.onAction(msg.name)
+	case palette.EventClosed:
+		m.usingPalette = false
 	case tea.WindowSizeMsg:
 		return m, m.onResize(msg)
 	case tea.KeyMsg:
+		if modalCmd, ok := m.onModalKey(msg); ok {
+			return m, modalCmd
+		}
+		if m.isReadOnly() && m.isWriteKey(msg) {
+			return m, func() tea.Msg {
+				return statusbar.EventToast{Err: ErrReadOnly}
+			}
+		}
 		cmd = m.onKey(msg)
+	case tea.MouseMsg:
+		if m.modalOpen() {
+			return m, nil
+		}
+		if msg.Y == m.height-m.statusbar.GetHeight() {
+			return m, m.onStatusbarClick(msg)
+		}
+	case columnmanager.EventColumnsUpdated:
+		cmd = m.onColumnsUpdated(msg)
+	case columnmanager.EventLayoutToggled:
+		cmd = m.onLayoutToggled()
+	case columnmanager.EventColumnsReset:
+		cmd = m.onColumnsReset()
+	case columnmanager.EventClosed:
+		cmd = m.onColumnsClosed()
+	case popup.EventClosed:
+		m.showingPopup = false
+	case navigator.EventConfirm:
+		cmd = m.onConfirm(msg)
+	case EventReconciled:
+		cmd = m.onReconciled(msg)
+	case confirm.EventClosed:
+		m.confirming = false
+	case navigator.EventShowFullHelp:
+		m.onShowKeyHelp()
+	case keyhelp.EventClosed:
+		m.showingKeyHelp = false
+	case picker.EventClosed:
+		m.picking = false
+	case navigator.EventSelectionChanged:
+		m.setIndicators()
 	case navigator.EventItemFocused:
 		m.statusbar.SetPath(m.pathByData[msg.ID])
+	case navigator.EventOnlyUnhealthyToggled:
+		m.onlyUnhealthy = msg.Enabled
+		m.setIndicators()
+	case navigator.EventZoomed:
+		m.zoomedID = msg.ID
+		m.setIndicators()
 	}
 
 	if !m.ready {
@@ -37,20 +114,99 @@ func (m Model) Update(msg tea.Msg) (Model, tea.Cmd) {
 	return m, tea.Batch(cmd, navigatorCmd, statusBarCmd)
 }
 
+// onTraced shows the trace, unless it belongs to a previous target.
+func (m *Model) onTraced(msg eventTraced) tea.Cmd {
+	if msg.generation != m.generation {
+		return nil
+	}
+	if msg.err != nil {
+		return func() tea.Msg { return msg.err }
+	}
+	return m.onCrossplaneUpdate(msg.trace)
+}
+
+// onWatchTick gets the trace again, unless the target changed meanwhile.
+func (m *Model) onWatchTick(msg eventWatchTick) tea.Cmd {
+	if msg.generation != m.generation {
+		return nil
+	}
+	return m.getTrace()
+}
+
 func (m *Model) onCrossplaneUpdate(data *xplane.Resource) tea.Cmd {
 	if data == nil {
 		return nil
 	}
 
-	m.setColumns(data.Unstructured.GroupVersionKind().GroupKind())
+	m.setColumns(data)
 	m.setData(data)
+	m.setIndicators()
 
-	if m.watch {
-		return tea.Tick(m.watchInterval, func(_ time.Time) tea.Msg {
-			return m.getTrace()()
-		})
+	if !m.watch {
+		return nil
 	}
-	return nil
+	if m.externalWatch {
+		return m.notify(data)
+	}
+
+	generation := m.generation
+	return tea.Batch(m.notify(data), tea.Tick(m.watchInterval, func(_ time.Time) tea.Msg {
+		return eventWatchTick{generation: generation}
+	}))
+}
+
+func (m *Model) notify(data *xplane.Resource) tea.Cmd {
+	if m.notifier == nil {
+		return nil
+	}
+
+	notifier := m.notifier
+	return func() tea.Msg {
+		if err := notifier.Observe(context.Background(), data); err != nil {
+			m.logger.Error("failed to notify transitions", "error", err)
+			return statusbar.EventToast{Err: err}
+		}
+		return nil
+	}
+}
+
+// ErrReadOnly is shown when trying to change resources in read-only mode.
+var ErrReadOnly = errors.New("read-only mode: resources can not be changed")
+
+func (m Model) isReadOnly() bool {
+	return m.readOnly || slices.Contains(m.readOnlyContexts, m.target.Context)
+}
+
+// isWriteKey reports if a key triggers an action changing resources.
+func (m Model) isWriteKey(msg tea.KeyMsg) bool {
+	if !m.ready || m.navigator.InputFocused() {
+		return false
+	}
+
+	for _, a := range m.navigator.Actions() {
+		if a.Writes && key.Matches(msg, a.Binding) {
+			return true
+		}
+	}
+	return false
+}
+
+// setIndicators shows which view modes are enabled in the statusbar.
+func (m *Model) setIndicators() {
+	indicators := []string{}
+	if count := m.navigator.SelectionCount(); count > 0 {
+		indicators = append(indicators, fmt.Sprintf("%d selected", count))
+	}
+	if m.isReadOnly() {
+		indicators = append(indicators, "read-only")
+	}
+	if m.onlyUnhealthy {
+		indicators = append(indicators, "only unhealthy")
+	}
+	if path := m.pathByData[m.zoomedID]; len(path) > 0 {
+		indicators = append(indicators, "zoom: "+path[len(path)-1])
+	}
+	m.statusbar.SetIndicators(indicators)
 }
 
 func (m *Model) onResize(msg tea.WindowSizeMsg) tea.Cmd {
@@ -63,9 +219,144 @@ func (m *Model) onResize(msg tea.WindowSizeMsg) tea.Cmd {
 
 	m.statusbar, statusbarCmd = m.statusbar.Update(msg)
 
+	m.columnManager, _ = m.columnManager.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height - m.statusbar.GetHeight()})
+	m.popup, _ = m.popup.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height - m.statusbar.GetHeight()})
+	m.confirm, _ = m.confirm.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height - m.statusbar.GetHeight()})
+	m.keyHelp, _ = m.keyHelp.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height - m.statusbar.GetHeight()})
+	m.picker, _ = m.picker.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height - m.statusbar.GetHeight()})
+	m.palette, _ = m.palette.Update(msg)
+
+	// The layout might change, as it depends on the terminal width by default
+	m.refreshColumns()
+
 	return tea.Batch(navigatorCmd, statusbarCmd)
 }
 
-func (m *Model) onKey(_ tea.KeyMsg) tea.Cmd {
+// modalOpen tells whether a modal (or the palette) is shown over the trace.
+func (m Model) modalOpen() bool {
+	return m.managingColumns || m.showingPopup || m.confirming || m.usingPalette || m.showingKeyHelp || m.picking
+}
+
+// onModalKey sends the key to the modal shown over the trace, if any, as it
+// takes all keys while open.
+func (m *Model) onModalKey(msg tea.KeyMsg) (tea.Cmd, bool) {
+	var cmd tea.Cmd
+	switch {
+	case m.managingColumns:
+		m.columnManager, cmd = m.columnManager.Update(msg)
+	case m.showingPopup:
+		m.popup, cmd = m.popup.Update(msg)
+	case m.confirming:
+		m.confirm, cmd = m.confirm.Update(msg)
+	case m.showingKeyHelp:
+		m.keyHelp, cmd = m.keyHelp.Update(msg)
+	case m.picking:
+		m.picker, cmd = m.picker.Update(msg)
+	case m.usingPalette:
+		m.palette, cmd = m.palette.Update(msg)
+	default:
+		return nil, false
+	}
+	return cmd, true
+}
+
+func (m *Model) onKey(msg tea.KeyMsg) tea.Cmd {
+	if !m.ready || m.navigator.InputFocused() {
+		return nil
+	}
+
+	switch {
+	case key.Matches(msg, m.keyMap.ExportDOT):
+		return m.export(export.FormatDOT, "")
+	case key.Matches(msg, m.keyMap.ExportMermaid):
+		return m.export(export.FormatMermaid, "")
+	case key.Matches(msg, m.keyMap.ManageColumns):
+		return m.onManageColumns()
+	case key.Matches(msg, m.keyMap.Command):
+		return m.onCommand()
+	}
+	for _, a := range m.actions() {
+		if key.Matches(msg, a.binding) {
+			return a.run(m)
+		}
+	}
 	return nil
 }
+
+// onConfirm shows which objects an action is about to affect, only sending
+// the action once confirmed.
+func (m *Model) onConfirm(msg navigator.EventConfirm) tea.Cmd {
+	items := make([]string, 0, len(msg.Items))
+	for _, item := range msg.Items {
+		items = append(items, item.ID)
+	}
+
+	m.confirming = true
+	return m.confirm.Ask(confirm.Request{
+		Title:     msg.Title,
+		Items:     items,
+		Input:     msg.Input,
+		OnConfirm: msg.OnConfirm,
+	})
+}
+
+// onStatusbarClick jumps to the ancestor of a clicked breadcrumb segment.
+func (m *Model) onStatusbarClick(msg tea.MouseMsg) tea.Cmd {
+	if msg.Action != tea.MouseActionPress || msg.Button != tea.MouseButtonLeft {
+		return nil
+	}
+
+	depth, ok := m.statusbar.PathSegmentAt(msg.X)
+	if !ok {
+		return nil
+	}
+	return m.navigator.FocusAncestor(depth)
+}
+
+// onShowMessage shows the full status message and conditions of the focused
+// resource, as these are usually truncated in the table.
+func (m *Model) onShowMessage() tea.Cmd {
+	current := m.navigator.Current()
+	r, ok := current.Data.(*xplane.Resource)
+	if !ok {
+		return nil
+	}
+
+	status, _ := xplane.GetStatus(r, xplane.IsPkg(m.kind))
+	lines := []string{status}
+	if r.Error != nil {
+		lines = append(lines, "", "Error: "+r.Error.Error())
+	}
+
+	if conditions := r.GetConditions(); len(conditions) > 0 {
+		lines = append(lines, "", "Conditions:")
+		for _, c := range conditions {
+			line := fmt.Sprintf("- %s=%s", c.Type, c.Status)
+			if c.Reason != "" {
+				line += " (" + string(c.Reason) + ")"
+			}
+			if c.Message != "" {
+				line += ": " + c.Message
+			}
+			lines = append(lines, line)
+		}
+	}
+
+	m.popup.SetText(current.ID, strings.TrimSpace(strings.Join(lines, "\n")))
+	m.showingPopup = true
+	return nil
+}
+
+// export writes the trace into dst or, if empty, into the current directory.
+func (m *Model) export(f export.Format, dst string) tea.Cmd {
+	trace := m.trace
+	return func() tea.Msg {
+		if dst == "" {
+			dst = export.Filename(f, trace)
+		}
+		if err := export.WriteFile(dst, f, trace, export.Meta{CapturedAt: time.Now()}); err != nil {
+			return statusbar.EventToast{Err: err}
+		}
+		return statusbar.EventToast{Message: "exported to " + dst}
+	}
+}
@@ -0,0 +1,56 @@
+package xpnavigator
+
+import (
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/keyhelp"
+	"github.com/brunoluiz/xpdig/internal/bubbles/keys"
+	"github.com/charmbracelet/bubbles/key"
+)
+
+// onShowKeyHelp shows every key binding, grouped by what they do, including
+// the ones of the table (not taken by the navigator) and of the modals.
+func (m *Model) onShowKeyHelp() {
+	nav, table, k := m.navigator.KeyMap, m.navigator.TableKeyMap(), m.keyMap
+	groups := []keyhelp.Group{
+		{Title: "Movement", Bindings: []key.Binding{
+			nav.Up, nav.Down, nav.Top, nav.Bottom,
+			table.PageUp, table.PageDown, table.HalfPageUp, table.HalfPageDown,
+			table.GotoTop, table.GotoBottom, table.ScrollLeft, table.ScrollRight,
+			nav.Parent, nav.FirstChild, nav.NextSibling, nav.PrevSibling,
+		}},
+		{Title: "Tree", Bindings: []key.Binding{
+			nav.ToggleCollapse, nav.CollapseLevel, nav.ExpandLevel, nav.ExpandUnhealthy,
+			nav.OnlyUnhealthy, nav.ZoomIn, nav.ZoomOut, nav.Wrap,
+		}},
+		{Title: "Search", Bindings: []key.Binding{
+			nav.Search, nav.Filter, nav.SearchNext, nav.SearchPrevious,
+			nav.SearchMode, nav.SearchConfirm, nav.SearchQuit,
+		}},
+		{Title: "References", Bindings: []key.Binding{k.References, k.Back, k.Forward}},
+		{Title: "Selection", Bindings: []key.Binding{nav.Select, nav.SelectSubtree, nav.SelectMatches}},
+		{Title: "Actions", Bindings: []key.Binding{
+			nav.Describe, nav.Get, nav.Edit, nav.Delete, nav.Copy, nav.Annotate, nav.Pause, nav.PauseSubtree, nav.Reconcile,
+			k.ShowMessage, k.ExportDOT, k.ExportMermaid,
+		}},
+		{Title: "General", Bindings: []key.Binding{k.ManageColumns, k.Command, nav.Help, nav.ShowFullHelp, nav.Quit}},
+	}
+	groups = append(groups, m.helpGroups...)
+	groups = append(groups, []keyhelp.Group{
+		{Title: "Columns", Bindings: bindings(keys.Actions("columns", m.columnManager.KeyMap))},
+		{Title: "Message", Bindings: bindings(keys.Actions("popup", m.popup.KeyMap))},
+		{Title: "Confirmation", Bindings: bindings(keys.Actions("confirm", m.confirm.KeyMap))},
+		{Title: "Command line", Bindings: bindings(keys.Actions("palette", m.palette.KeyMap))},
+		{Title: "Key bindings", Bindings: bindings(keys.Actions("help", m.keyHelp.KeyMap))},
+		{Title: "References list", Bindings: bindings(keys.Actions("references", m.picker.KeyMap))},
+	}...)
+
+	m.showingKeyHelp = true
+	m.keyHelp.SetGroups(groups)
+}
+
+func bindings(actions []keys.Action) []key.Binding {
+	b := make([]key.Binding, 0, len(actions))
+	for _, a := range actions {
+		b = append(b, a.Binding)
+	}
+	return b
+}
@@ -1,8 +1,52 @@
 package xpnavigator
 
-type KeyMap struct{}
+import "github.com/charmbracelet/bubbles/key"
+
+type KeyMap struct {
+	ExportDOT     key.Binding
+	ExportMermaid key.Binding
+	ManageColumns key.Binding
+	ShowMessage   key.Binding
+	Command       key.Binding
+	References    key.Binding
+	Back          key.Binding
+	Forward       key.Binding
+}
 
 // DefaultKeyMap returns a default set of keybindings.
 func DefaultKeyMap() KeyMap {
-	return KeyMap{}
+	return KeyMap{
+		ExportDOT: key.NewBinding(
+			key.WithKeys("x"),
+			key.WithHelp("x", "export (dot)"),
+		),
+		ExportMermaid: key.NewBinding(
+			key.WithKeys("X"),
+			key.WithHelp("X", "export (mermaid)"),
+		),
+		ManageColumns: key.NewBinding(
+			key.WithKeys("C"),
+			key.WithHelp("C", "columns"),
+		),
+		ShowMessage: key.NewBinding(
+			key.WithKeys("m"),
+			key.WithHelp("m", "full message"),
+		),
+		Command: key.NewBinding(
+			key.WithKeys(":"),
+			key.WithHelp(":", "command"),
+		),
+		References: key.NewBinding(
+			key.WithKeys("r"),
+			key.WithHelp("r", "references"),
+		),
+		Back: key.NewBinding(
+			key.WithKeys("b"),
+			key.WithHelp("b", "back"),
+		),
+		Forward: key.NewBinding(
+			key.WithKeys("B"),
+			key.WithHelp("B", "forward"),
+		),
+	}
 }
@@ -0,0 +1,192 @@
+package xpnavigator
+
+import (
+	"fmt"
+	"maps"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/columnmanager"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/statusbar"
+	"github.com/brunoluiz/xpdig/internal/config"
+	"github.com/brunoluiz/xpdig/internal/xplane"
+	tea "github.com/charmbracelet/bubbletea"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	layoutTypeResource = "resource"
+	layoutTypePackage  = "package"
+
+	// minStatusWidth is how much room the STATUS column needs for the wide
+	// layout to be picked automatically
+	minStatusWidth = 40
+)
+
+// layoutColumn is a column of the current layout, with src being its index
+// in the default columns (see getColumns and withCustomColumns).
+type layoutColumn struct {
+	config.LayoutColumn
+	src int
+}
+
+func layoutType(gk schema.GroupKind) string {
+	if xplane.IsPkg(gk) {
+		return layoutTypePackage
+	}
+	return layoutTypeResource
+}
+
+func layoutMode(short bool) config.LayoutMode {
+	if short {
+		return config.LayoutModeShort
+	}
+	return config.LayoutModeWide
+}
+
+// isShort reports if the short layout is used: either pinned by flag, chosen
+// previously in the column manager or, by default, if the wide one does not fit.
+func (m Model) isShort(gk schema.GroupKind) bool {
+	if m.shortPinned {
+		return m.short
+	}
+	if mode := m.layouts[layoutType(gk)].Mode; mode != "" {
+		return mode == config.LayoutModeShort
+	}
+
+	wide := WideObjectColumnLayout
+	if xplane.IsPkg(gk) {
+		wide = WidePkgColumnLayout
+	}
+
+	cols := m.withCustomColumns(m.getColumns(wide))
+	w := minStatusWidth
+	for _, col := range cols[:len(cols)-1] {
+		w += col.Width + 3
+	}
+	return m.width < w
+}
+
+// layoutColumns returns the default columns, ordered, resized and hidden
+// according to the layout customised in the column manager. The tree column
+// is always kept first.
+func (m Model) layoutColumns(gk schema.GroupKind) []layoutColumn {
+	defaults := m.withCustomColumns(m.getColumns(m.getLayout(gk)))
+	if len(defaults) == 0 {
+		return []layoutColumn{}
+	}
+
+	cols := []layoutColumn{{LayoutColumn: config.LayoutColumn{Title: defaults[0].Title, Width: defaults[0].Width}}}
+	used := make([]bool, len(defaults))
+	used[0] = true
+
+	custom := m.layouts[layoutType(gk)].Columns[layoutMode(m.isShort(gk))]
+	for _, c := range custom {
+		for i, d := range defaults {
+			if used[i] || d.Title != c.Title {
+				continue
+			}
+			used[i] = true
+			if i == 0 {
+				cols[0].Width = c.Width
+				break
+			}
+			cols = append(cols, layoutColumn{LayoutColumn: c, src: i})
+			break
+		}
+	}
+
+	// Columns not customised yet (eg: new custom columns) keep their defaults
+	for i, d := range defaults {
+		if !used[i] {
+			cols = append(cols, layoutColumn{LayoutColumn: config.LayoutColumn{Title: d.Title, Width: d.Width}, src: i})
+		}
+	}
+
+	for i := range cols {
+		if cols[i].Width <= 0 {
+			cols[i].Width = defaults[cols[i].src].Width
+		}
+	}
+	return cols
+}
+
+func (m *Model) onManageColumns() tea.Cmd {
+	m.managingColumns = true
+	m.setManagedColumns()
+	return nil
+}
+
+func (m *Model) setManagedColumns() {
+	gk := m.kind
+	cols := []columnmanager.Column{}
+	for i, c := range m.layoutColumns(gk) {
+		cols = append(cols, columnmanager.Column{Title: c.Title, Width: c.Width, Hidden: c.Hidden, Fixed: i == 0})
+	}
+
+	title := fmt.Sprintf("Columns (%s %s layout)", layoutMode(m.isShort(gk)), layoutType(gk))
+	m.columnManager.SetColumns(title, cols)
+}
+
+func (m *Model) onColumnsUpdated(msg columnmanager.EventColumnsUpdated) tea.Cmd {
+	cols := []config.LayoutColumn{}
+	for _, c := range msg.Columns {
+		cols = append(cols, config.LayoutColumn{Title: c.Title, Width: c.Width, Hidden: c.Hidden})
+	}
+
+	layout := m.currentLayout()
+	layout.Columns[layoutMode(m.isShort(m.kind))] = cols
+	m.layouts[layoutType(m.kind)] = layout
+	m.refreshColumns()
+	return nil
+}
+
+func (m *Model) onLayoutToggled() tea.Cmd {
+	layout := m.currentLayout()
+	layout.Mode = layoutMode(!m.isShort(m.kind))
+	m.layouts[layoutType(m.kind)] = layout
+	m.shortPinned = false
+	m.refreshColumns()
+	m.setManagedColumns()
+	return nil
+}
+
+func (m *Model) onColumnsReset() tea.Cmd {
+	layout := m.currentLayout()
+	delete(layout.Columns, layoutMode(m.isShort(m.kind)))
+	m.layouts[layoutType(m.kind)] = layout
+	m.refreshColumns()
+	m.setManagedColumns()
+	return nil
+}
+
+func (m *Model) onColumnsClosed() tea.Cmd {
+	m.managingColumns = false
+	if m.layoutStore == nil {
+		return nil
+	}
+
+	store, layouts := m.layoutStore, maps.Clone(m.layouts)
+	return func() tea.Msg {
+		if err := store.Save(layouts); err != nil {
+			m.logger.Error("failed to save layouts", "error", err)
+			return statusbar.EventToast{Err: err}
+		}
+		return statusbar.EventToast{Message: "layout saved"}
+	}
+}
+
+func (m Model) currentLayout() config.Layout {
+	layout := m.layouts[layoutType(m.kind)]
+	if layout.Columns == nil {
+		layout.Columns = map[config.LayoutMode][]config.LayoutColumn{}
+	}
+	return layout
+}
+
+// refreshColumns re-renders the trace, after its layout changed.
+func (m *Model) refreshColumns() {
+	if m.trace == nil {
+		return
+	}
+	m.setColumns(m.trace)
+	m.setData(m.trace)
+}
@@ -1,18 +1,28 @@
 package xpnavigator
 
 import (
+	"context"
 	"fmt"
 	"log/slog"
+	"slices"
 	"time"
 
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/columnmanager"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/confirm"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/keyhelp"
 	"github.com/brunoluiz/xpdig/internal/bubbles/component/navigator"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/palette"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/picker"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/popup"
 	"github.com/brunoluiz/xpdig/internal/bubbles/component/statusbar"
 	"github.com/brunoluiz/xpdig/internal/bubbles/component/table"
+	"github.com/brunoluiz/xpdig/internal/config"
 	"github.com/brunoluiz/xpdig/internal/xplane"
+	"github.com/brunoluiz/xpdig/internal/xplane/column"
+	"github.com/brunoluiz/xpdig/internal/xplane/query"
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	"github.com/charmbracelet/x/ansi"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -39,22 +49,79 @@ type Tracer interface {
 	GetTrace() (*xplane.Resource, error)
 }
 
+// Notifier is told about every trace received, so it can act on transitions.
+type Notifier interface {
+	Observe(ctx context.Context, r *xplane.Resource) error
+}
+
+// LayoutStore persists layouts customised in the column manager.
+type LayoutStore interface {
+	Load() (config.Layouts, error)
+	Save(config.Layouts) error
+}
+
 type Model struct {
 	keyMap        KeyMap
+	styles        Styles
+	glyphs        Glyphs
 	navigator     navigator.Model
 	statusbar     statusbar.Model
 	tracer        Tracer
+	notifier      Notifier
 	width         int
 	height        int
 	short         bool
+	shortPinned   bool
 	watch         bool
 	watchInterval time.Duration
+	externalWatch bool
 	logger        *slog.Logger
 	ready         bool
 	spinner       spinner.Model
 
+	columns       []*column.Column
+	activeColumns []*column.Column
+	columnRefs    []int
+
+	layouts         config.Layouts
+	layoutStore     LayoutStore
+	columnManager   columnmanager.Model
+	managingColumns bool
+
+	popup        popup.Model
+	showingPopup bool
+
+	confirm    confirm.Model
+	confirming bool
+
+	picker  picker.Model
+	picking bool
+
+	keyHelp        keyhelp.Model
+	helpGroups     []keyhelp.Group
+	showingKeyHelp bool
+
+	palette      palette.Model
+	usingPalette bool
+
+	readOnly         bool
+	readOnlyContexts []string
+
+	target     Target
+	retarget   func(Target) Tracer
+	back       []Target
+	forward    []Target
+	contexts   []string
+	generation int
+
 	kind       schema.GroupKind
+	trace      *xplane.Resource
 	pathByData map[string][]string
+	health     Health
+
+	// onlyUnhealthy and zoomedID are shown as statusbar indicators
+	onlyUnhealthy bool
+	zoomedID      string
 }
 
 type WithOpt func(*Model)
@@ -71,9 +138,157 @@ func WithWatchInterval(t time.Duration) func(*Model) {
 	}
 }
 
+// WithExternalWatch stops the model from scheduling its own refreshes while
+// watching, as these are scheduled elsewhere through Refresh (eg: shared
+// between tabs).
+func WithExternalWatch() func(*Model) {
+	return func(m *Model) {
+		m.externalWatch = true
+	}
+}
+
+func WithNotifier(n Notifier) func(*Model) {
+	return func(m *Model) {
+		m.notifier = n
+	}
+}
+
+// WithFilter starts the navigator filtering by a query (see xplane/query).
+func WithFilter(q string) func(*Model) {
+	return func(m *Model) {
+		if q == "" {
+			return
+		}
+		if err := m.navigator.SetFilter(q); err != nil {
+			m.logger.Error("invalid filter", "filter", q, "error", err)
+		}
+	}
+}
+
+// WithOnlyUnhealthy starts the navigator showing only unhealthy resources.
+func WithOnlyUnhealthy(enabled bool) func(*Model) {
+	return func(m *Model) {
+		m.navigator.SetOnlyUnhealthy(enabled)
+		m.onlyUnhealthy = enabled
+		m.setIndicators()
+	}
+}
+
+// WithColumns adds user-defined columns, shown before the STATUS column.
+func WithColumns(cols []*column.Column) func(*Model) {
+	return func(m *Model) {
+		m.columns = cols
+	}
+}
+
+// WithTarget sets what is being traced, with retarget returning tracers for
+// other targets (eg: `:ns`, `:ctx` and `:trace`).
+func WithTarget(t Target, retarget func(Target) Tracer) func(*Model) {
+	return func(m *Model) {
+		m.target = t
+		m.retarget = retarget
+	}
+}
+
+// WithReadOnly disables actions changing resources, either always or only
+// while tracing resources of some Kubernetes contexts.
+func WithReadOnly(enabled bool, contexts []string) func(*Model) {
+	return func(m *Model) {
+		m.readOnly = enabled
+		m.readOnlyContexts = contexts
+		m.setIndicators()
+	}
+}
+
+// WithKeyMap replaces the default keybindings.
+func WithKeyMap(km KeyMap) func(*Model) {
+	return func(m *Model) { m.keyMap = km }
+}
+
+// WithStyles replaces the default styles.
+func WithStyles(s Styles) func(*Model) {
+	return func(m *Model) {
+		m.styles = s
+		m.spinner.Style = s.Spinner
+	}
+}
+
+// WithHelpGroups adds key bindings handled outside of the trace (eg: tabs) to
+// the key bindings overlay.
+func WithHelpGroups(groups ...keyhelp.Group) func(*Model) {
+	return func(m *Model) { m.helpGroups = groups }
+}
+
+// WithGlyphs replaces the default health glyphs.
+func WithGlyphs(g Glyphs) func(*Model) {
+	return func(m *Model) { m.glyphs = g }
+}
+
+// WithModalStyles replaces the default styles of the modals (column manager,
+// popup, confirmation, command palette, key bindings and references).
+func WithModalStyles(
+	columns columnmanager.Styles,
+	popupStyles popup.Styles,
+	confirmStyles confirm.Styles,
+	paletteStyles palette.Styles,
+	keyHelpStyles keyhelp.Styles,
+	pickerStyles picker.Styles,
+) func(*Model) {
+	return func(m *Model) {
+		m.columnManager.Styles = columns
+		m.popup.Styles = popupStyles
+		m.confirm.Styles = confirmStyles
+		m.palette.Styles = paletteStyles
+		m.keyHelp.Styles = keyHelpStyles
+		m.picker.Styles = pickerStyles
+	}
+}
+
+// WithModalKeyMaps replaces the default keybindings of the modals (column
+// manager, popup, confirmation, command palette, key bindings and references).
+func WithModalKeyMaps(
+	columns columnmanager.KeyMap,
+	popupKeys popup.KeyMap,
+	confirmKeys confirm.KeyMap,
+	paletteKeys palette.KeyMap,
+	keyHelpKeys keyhelp.KeyMap,
+	pickerKeys picker.KeyMap,
+) func(*Model) {
+	return func(m *Model) {
+		m.columnManager.KeyMap = columns
+		m.popup.KeyMap = popupKeys
+		m.confirm.KeyMap = confirmKeys
+		m.palette.KeyMap = paletteKeys
+		m.keyHelp.KeyMap = keyHelpKeys
+		m.picker.KeyMap = pickerKeys
+	}
+}
+
+// WithContexts sets which Kubernetes contexts are suggested by `:ctx`.
+func WithContexts(contexts []string) func(*Model) {
+	return func(m *Model) {
+		m.contexts = contexts
+	}
+}
+
+// WithLayoutStore loads and saves layouts customised in the column manager.
+func WithLayoutStore(s LayoutStore) func(*Model) {
+	return func(m *Model) {
+		layouts, err := s.Load()
+		if err != nil {
+			m.logger.Error("failed to load layouts", "error", err)
+		}
+		m.layouts = layouts
+		m.layoutStore = s
+	}
+}
+
+// WithShortColumns pins the short (or wide) layout, instead of choosing it
+// based on the terminal width or the layouts chosen previously.
 func WithShortColumns(enabled bool) func(*Model) {
 	return func(m *Model) {
 		m.short = enabled
+		m.shortPinned = true
 	}
 }
 
@@ -86,10 +301,12 @@ func New(
 ) Model {
 	s := spinner.New()
 	s.Spinner = spinner.Dot
-	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	s.Style = DefaultStyles().Spinner
 
 	m := Model{
 		keyMap:        DefaultKeyMap(),
+		styles:        DefaultStyles(),
+		glyphs:        DefaultGlyphs(),
 		logger:        logger,
 		navigator:     navModel,
 		statusbar:     statusModel,
@@ -97,12 +314,20 @@ func New(
 		width:         0,
 		height:        0,
 		watchInterval: 10 * time.Second,
-		short:         true,
+		layouts:       config.Layouts{},
+		columnManager: columnmanager.New(),
+		popup:         popup.New(),
+		confirm:       confirm.New(),
+		keyHelp:       keyhelp.New(),
+		picker:        picker.New(),
+		palette:       palette.New(),
 		pathByData:    map[string][]string{},
 		ready:         false,
 		spinner:       s,
 	}
 
+	m.navigator.SetMatcherFactory(newMatcher)
+
 	for _, opt := range opts {
 		opt(&m)
 	}
@@ -110,13 +335,39 @@ func New(
 	return m
 }
 
+// newMatcher compiles search queries using the xplane/query language.
+func newMatcher(q string) (navigator.Matcher, error) {
+	expr, err := query.Parse(q)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(row navigator.DataRow) bool {
+		r, ok := row.Data.(*xplane.Resource)
+		if !ok {
+			return false
+		}
+		return expr.Match(query.NewSubject(row.ID, r))
+	}, nil
+}
+
+// eventTraced is a trace of a given generation, with the generation changing
+// whenever the target changes (see onRetargeted).
+type eventTraced struct {
+	generation int
+	trace      *xplane.Resource
+	err        error
+}
+
+type eventWatchTick struct {
+	generation int
+}
+
 func (m Model) getTrace() tea.Cmd {
+	tracer, generation := m.tracer, m.generation
 	return func() tea.Msg {
-		res, err := m.tracer.GetTrace()
-		if err != nil {
-			return err
-		}
-		return res
+		res, err := tracer.GetTrace()
+		return eventTraced{generation: generation, trace: res, err: err}
 	}
 }
 
@@ -133,11 +384,27 @@ func (m Model) View() string {
 		)
 	}
 
-	return lipgloss.JoinVertical(
-		lipgloss.Left,
-		m.navigator.View(),
-		m.statusbar.View(),
-	)
+	main := m.navigator.View()
+	switch {
+	case m.managingColumns:
+		main = m.columnManager.View()
+	case m.showingPopup:
+		main = m.popup.View()
+	case m.confirming:
+		main = m.confirm.View()
+	case m.showingKeyHelp:
+		main = m.keyHelp.View()
+	case m.picking:
+		main = m.picker.View()
+	}
+
+	// The palette takes the place of the statusbar while typing a command
+	bottom := m.statusbar.View()
+	if m.usingPalette {
+		bottom = m.palette.View()
+	}
+
+	return lipgloss.JoinVertical(lipgloss.Left, main, bottom)
 }
 
 type ColumnLayout int
@@ -196,8 +463,8 @@ func (m Model) getColumns(layout ColumnLayout) []table.Column {
 }
 
 func (m Model) getLayout(gk schema.GroupKind) ColumnLayout {
-	isPkg := xplane.IsPkg(gk)
-	isRes, isWide, isShort := !isPkg, !m.short, m.short
+	isPkg, isShort := xplane.IsPkg(gk), m.isShort(gk)
+	isRes, isWide := !isPkg, !isShort
 
 	switch {
 	case isPkg && isShort:
@@ -213,48 +480,162 @@ func (m Model) getLayout(gk schema.GroupKind) ColumnLayout {
 	}
 }
 
-func (m *Model) setColumns(gk schema.GroupKind) {
-	m.navigator.SetColumns(m.getColumns(m.getLayout(gk)))
+func (m *Model) setColumns(data *xplane.Resource) {
+	// Custom columns are only shown if any resource in the trace is in their scope
+	m.activeColumns = []*column.Column{}
+	for _, c := range m.columns {
+		if anyResource(data, func(r *xplane.Resource) bool { return c.Matches(r.Unstructured.GroupVersionKind()) }) {
+			m.activeColumns = append(m.activeColumns, c)
+		}
+	}
+
+	gk := data.Unstructured.GroupVersionKind().GroupKind()
+	m.columnRefs = []int{}
+	cols := []table.Column{}
+	for _, c := range m.layoutColumns(gk) {
+		if !c.Hidden {
+			m.columnRefs = append(m.columnRefs, c.src)
+			cols = append(cols, table.Column{Title: c.Title, Width: c.Width})
+		}
+	}
+	m.navigator.SetColumns(cols)
+
+	// Fuzzy and regex searches look into object names and status messages
+	searchCols := []int{}
+	wrapCol := -1
+	for i, col := range cols {
+		if col.Title == HeaderKeyObject || col.Title == HeaderKeyStatus {
+			searchCols = append(searchCols, i)
+		}
+		if col.Title == HeaderKeyStatus {
+			wrapCol = i
+		}
+	}
+	m.navigator.SetSearchColumns(searchCols)
+	m.navigator.SetWrapColumn(wrapCol)
+}
+
+// withCustomColumns inserts the active custom columns before the last
+// column (STATUS), which takes the remaining width.
+func (m Model) withCustomColumns(cols []table.Column) []table.Column {
+	if len(cols) == 0 {
+		return cols
+	}
+
+	custom := []table.Column{}
+	for _, c := range m.activeColumns {
+		custom = append(custom, table.Column{Title: c.Title, Width: c.Width})
+	}
+	return slices.Insert(cols, len(cols)-1, custom...)
+}
+
+func (m Model) withCustomValues(v *xplane.Resource, values []string) []string {
+	if len(values) == 0 {
+		return values
+	}
+
+	gvk := v.Unstructured.GroupVersionKind()
+	custom := []string{}
+	for _, c := range m.activeColumns {
+		value := ""
+		if c.Matches(gvk) {
+			value = c.Value(v.Unstructured.Object)
+		}
+		custom = append(custom, value)
+	}
+	return slices.Insert(values, len(values)-1, custom...)
+}
+
+func anyResource(r *xplane.Resource, fn func(*xplane.Resource) bool) bool {
+	if fn(r) {
+		return true
+	}
+	for _, c := range r.Children {
+		if anyResource(c, fn) {
+			return true
+		}
+	}
+	return false
 }
 
 func (m *Model) setData(data *xplane.Resource) {
 	m.ready = true
+	m.trace = data
 	rows := []navigator.DataRow{}
 	m.kind = data.Unstructured.GroupVersionKind().GroupKind()
-	m.traceToRows(data, &rows, 0, []string{}, []bool{})
+	m.traceToRows(data, &rows, 0, []string{})
 	m.navigator.SetData(rows)
+
+	m.health = Health{
+		Loaded:    true,
+		Name:      fmt.Sprintf("%s/%s", data.Unstructured.GetKind(), data.Unstructured.GetName()),
+		Resources: len(rows),
+	}
+	for _, row := range rows {
+		if row.Unhealthy {
+			m.health.Unhealthy++
+		}
+	}
+}
+
+// Health summarises the trace, so it can be shown elsewhere (eg: tabs).
+type Health struct {
+	Loaded    bool
+	Name      string
+	Resources int
+	Unhealthy int
+}
+
+func (m Model) Health() Health { return m.health }
+
+// Target returns what is being traced, which is empty if not tracing live
+// resources.
+func (m Model) Target() Target { return m.target }
+
+// Refresh loads the trace again, as long as live resources are traced (eg:
+// stdin can not be read twice).
+func (m Model) Refresh() tea.Cmd {
+	if m.retarget == nil {
+		return nil
+	}
+	return m.getTrace()
 }
 
-func (m Model) traceToRows(v *xplane.Resource, rows *[]navigator.DataRow, depth int, currentPath []string, isLastChilds []bool) {
+// CapturesKeys returns if keys are taken by a modal or an input (eg: the
+// search), so these should not be handled elsewhere.
+func (m Model) CapturesKeys() bool {
+	return m.managingColumns || m.showingPopup || m.confirming || m.usingPalette ||
+		m.showingKeyHelp || m.picking || m.navigator.InputFocused()
+}
+
+// glyph returns the health glyph of a resource, with paused taking precedence.
+func (m Model) glyph(v *xplane.Resource, unhealthy bool) string {
+	switch {
+	case v.IsPaused():
+		return m.glyphs.Paused
+	case unhealthy:
+		return m.glyphs.Unhealthy
+	default:
+		return m.glyphs.Healthy
+	}
+}
+
+func (m Model) traceToRows(v *xplane.Resource, rows *[]navigator.DataRow, depth int, currentPath []string) {
 	name := fmt.Sprintf("%s/%s", v.Unstructured.GetKind(), v.Unstructured.GetName())
 	group := v.Unstructured.GetObjectKind().GroupVersionKind().Group
 	row := navigator.DataRow{
 		ID:      fmt.Sprintf("%s.%s/%s", v.Unstructured.GetKind(), group, v.Unstructured.GetName()),
 		Data:    v,
 		Columns: []string{},
+		Depth:   depth,
 	}
 
-	// Build tree prefix
-	var prefix string
-	if depth > 0 {
-		for i := 0; i < depth-1; i++ {
-			if isLastChilds[i] {
-				prefix += "   "
-			} else {
-				prefix += "│  "
-			}
-		}
-		if len(isLastChilds) > 0 && isLastChilds[depth-1] {
-			prefix += "└─ "
-		} else {
-			prefix += "├─ "
-		}
-	}
-	label := prefix + name
-
-	if v.Unstructured.GetAnnotations()["crossplane.io/paused"] == "true" {
+	// Tree branches are prefixed by the navigator, as it knows what is visible
+	label := name
+	if v.IsPaused() {
+		row.Paused = true
 		label += " (paused)"
-		row.Color = lipgloss.ANSIColor(ansi.Yellow)
+		row.Style = m.styles.Paused
 	}
 
 	var data map[string]string
@@ -272,8 +653,9 @@ func (m Model) traceToRows(v *xplane.Resource, rows *[]navigator.DataRow, depth
 			HeaderKeyStatus:        resStatus.Status,
 		}
 		if !resStatus.Ok {
-			row.Color = lipgloss.ANSIColor(ansi.Red)
+			row.Style = m.styles.Unhealthy
 		}
+		row.Unhealthy = !resStatus.Ok || v.Error != nil
 	} else {
 		resStatus := xplane.GetResourceStatus(v, label)
 		data = map[string]string{
@@ -286,12 +668,20 @@ func (m Model) traceToRows(v *xplane.Resource, rows *[]navigator.DataRow, depth
 			HeaderKeyStatus:     resStatus.Status,
 		}
 		if !resStatus.Ok {
-			row.Color = lipgloss.ANSIColor(ansi.Red)
+			row.Style = m.styles.Unhealthy
 		}
+		row.Unhealthy = !resStatus.Ok || v.Error != nil
 	}
 
+	data[HeaderKeyObject] = m.glyph(v, row.Unhealthy) + " " + label
+
+	values := []string{}
 	for _, col := range m.getColumns(m.getLayout(m.kind)) {
-		row.Columns = append(row.Columns, data[col.Title])
+		values = append(values, data[col.Title])
+	}
+	values = m.withCustomValues(v, values)
+	for _, ref := range m.columnRefs {
+		row.Columns = append(row.Columns, values[ref])
 	}
 	*rows = append(*rows, row)
 
@@ -302,9 +692,8 @@ func (m Model) traceToRows(v *xplane.Resource, rows *[]navigator.DataRow, depth
 	m.pathByData[row.ID] = path
 
 	// Recursively process children
-	for i, cv := range v.Children {
-		last := i == len(v.Children)-1
-		m.traceToRows(cv, rows, depth+1, path, append(isLastChilds, last))
+	for _, cv := range v.Children {
+		m.traceToRows(cv, rows, depth+1, path)
 	}
 }
 
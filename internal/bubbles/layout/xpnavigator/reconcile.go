@@ -0,0 +1,59 @@
+package xpnavigator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/navigator"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/statusbar"
+	"github.com/brunoluiz/xpdig/internal/xplane"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// EventReconciled is sent once items were asked to reconcile and the trace was
+// loaded again, with items holding the resources as these were before.
+type EventReconciled struct {
+	Items []navigator.Item
+	Err   error
+}
+
+// onReconciled tells whether the conditions of the reconciled resources
+// transitioned, comparing them with the trace loaded since.
+func (m *Model) onReconciled(msg EventReconciled) tea.Cmd {
+	if msg.Err != nil {
+		return func() tea.Msg { return statusbar.EventToast{Err: msg.Err} }
+	}
+
+	rows := m.rowsByID()
+	changed := 0
+	summary := ""
+	for _, item := range msg.Items {
+		before, ok := item.Data.(*xplane.Resource)
+		after, found := rows[item.ID]
+		if !ok || !found {
+			continue
+		}
+
+		changes := xplane.ChangedConditions(before, after)
+		if len(changes) == 0 {
+			continue
+		}
+		changed++
+		parts := make([]string, 0, len(changes))
+		for _, c := range changes {
+			parts = append(parts, c.String())
+		}
+		summary = strings.Join(parts, ", ")
+	}
+
+	var message string
+	switch {
+	case len(msg.Items) == 1 && changed == 0:
+		message = "reconciled " + msg.Items[0].ID + ": no condition transitioned yet"
+	case len(msg.Items) == 1:
+		message = "reconciled " + msg.Items[0].ID + ": " + summary
+	default:
+		message = fmt.Sprintf("reconciled %d objects: %d with condition transitions", len(msg.Items), changed)
+	}
+	return func() tea.Msg { return statusbar.EventToast{Message: message} }
+}
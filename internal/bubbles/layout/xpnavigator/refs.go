@@ -0,0 +1,102 @@
+package xpnavigator
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/picker"
+	"github.com/brunoluiz/xpdig/internal/bubbles/component/statusbar"
+	"github.com/brunoluiz/xpdig/internal/xplane"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ErrNoHistory is returned when going back or forward without traces to go to.
+var ErrNoHistory = errors.New("no trace to go to")
+
+// EventDescribeReference is sent when an object referenced by a resource (eg:
+// its ProviderConfig) is meant to be described.
+type EventDescribeReference struct {
+	Reference xplane.Reference
+}
+
+// historyMove is how a retarget changes the back/forward history, which works
+// like the one of browsers: tracing something new clears what was ahead.
+type historyMove int
+
+const (
+	historyPush historyMove = iota
+	historyBack
+	historyForward
+)
+
+// onShowReferences lists the objects referenced by the focused resource.
+func (m *Model) onShowReferences() tea.Cmd {
+	current := m.navigator.Current()
+	r, ok := current.Data.(*xplane.Resource)
+	if !ok {
+		return nil
+	}
+
+	refs := xplane.GetReferences(r)
+	items := make([]picker.Item, 0, len(refs))
+	for _, ref := range refs {
+		desc := ref.Field
+		if ref.Namespace != "" {
+			desc += " (" + ref.Namespace + ")"
+		}
+		items = append(items, picker.Item{Title: ref.ID(), Description: desc, Data: ref})
+	}
+
+	target := m.target
+	m.picking = true
+	m.picker.Ask(picker.Request{
+		Title: "References of " + current.ID,
+		Items: items,
+		OnChoose: func(item picker.Item, action picker.Action) tea.Msg {
+			ref, _ := item.Data.(xplane.Reference)
+			t := Target{Namespace: ref.Namespace, Context: target.Context, Kind: ref.Type(), Object: ref.Name}
+			switch action {
+			case picker.ActionDescribe:
+				return EventDescribeReference{Reference: ref}
+			case picker.ActionOpenTab:
+				return EventOpenTab{Target: t}
+			default:
+				return eventRetarget{target: t}
+			}
+		},
+	})
+	return nil
+}
+
+// onHistory traces the previous (or next) target, as in a browser.
+func (m *Model) onHistory(move historyMove) tea.Cmd {
+	history := m.back
+	if move == historyForward {
+		history = m.forward
+	}
+
+	if m.retarget == nil || len(history) == 0 {
+		err := ErrNoHistory
+		if m.retarget == nil {
+			err = ErrRetargetUnavailable
+		}
+		return func() tea.Msg { return statusbar.EventToast{Err: err} }
+	}
+	t := history[len(history)-1]
+	return func() tea.Msg { return eventRetarget{target: t, move: move} }
+}
+
+// record updates the history once the trace of another target is loaded.
+func (m *Model) record(move historyMove) {
+	switch move {
+	case historyBack:
+		m.back = slices.Delete(m.back, len(m.back)-1, len(m.back))
+		m.forward = append(m.forward, m.target)
+	case historyForward:
+		m.forward = slices.Delete(m.forward, len(m.forward)-1, len(m.forward))
+		m.back = append(m.back, m.target)
+	default:
+		m.back = append(m.back, m.target)
+		m.forward = nil
+	}
+}
@@ -0,0 +1,32 @@
+package xpnavigator
+
+import (
+	"github.com/brunoluiz/xpdig/internal/bubbles/theme"
+	"github.com/charmbracelet/lipgloss"
+)
+
+type Styles struct {
+	Spinner   lipgloss.Style
+	Unhealthy lipgloss.Style
+	Paused    lipgloss.Style
+}
+
+func DefaultStyles() Styles {
+	th := theme.Default()
+	return Styles{
+		Spinner:   th.Accent.Lipgloss(),
+		Unhealthy: th.Unhealthy.Lipgloss(),
+		Paused:    th.Paused.Lipgloss(),
+	}
+}
+
+// Glyphs prefix resources with their health, so it does not rely on colours.
+type Glyphs struct {
+	Healthy   string
+	Unhealthy string
+	Paused    string
+}
+
+func DefaultGlyphs() Glyphs {
+	return Glyphs(theme.Default().Glyphs)
+}
@@ -0,0 +1,228 @@
+// Package theme defines the colours and glyphs used by the UI, with built-in
+// dark, light, high-contrast and colourless (NO_COLOR) themes.
+package theme
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mistakenelf/teacup/statusbar"
+)
+
+const (
+	Auto         = "auto"
+	Dark         = "dark"
+	Light        = "light"
+	HighContrast = "high-contrast"
+	Mono         = "mono"
+)
+
+// ErrUnknownTheme is returned when a theme is neither built-in nor configured.
+type ErrUnknownTheme struct {
+	Name      string
+	Available []string
+}
+
+func (e *ErrUnknownTheme) Error() string {
+	return fmt.Sprintf("unknown theme '%s' (available: %s)", e.Name, strings.Join(e.Available, ", "))
+}
+
+// Style is how some text is shown. Colours are ANSI codes (eg: `1`) or hex
+// values (eg: `#ff0000`).
+type Style struct {
+	Foreground string `json:"fg,omitempty"`
+	Background string `json:"bg,omitempty"`
+	Bold       bool   `json:"bold,omitempty"`
+	Underline  bool   `json:"underline,omitempty"`
+	Reverse    bool   `json:"reverse,omitempty"`
+}
+
+// Lipgloss returns the style as a lipgloss.Style.
+func (s Style) Lipgloss() lipgloss.Style {
+	st := lipgloss.NewStyle().Bold(s.Bold).Underline(s.Underline).Reverse(s.Reverse)
+	if s.Foreground != "" {
+		st = st.Foreground(lipgloss.Color(s.Foreground))
+	}
+	if s.Background != "" {
+		st = st.Background(lipgloss.Color(s.Background))
+	}
+	return st
+}
+
+// StatusBar returns the style colours for a status bar column.
+func (s Style) StatusBar() statusbar.ColorConfig {
+	return statusbar.ColorConfig{
+		Foreground: lipgloss.AdaptiveColor{Light: s.Foreground, Dark: s.Foreground},
+		Background: lipgloss.AdaptiveColor{Light: s.Background, Dark: s.Background},
+	}
+}
+
+// Glyphs tell the health of resources apart without relying on colours.
+type Glyphs struct {
+	Healthy   string `json:"healthy,omitempty"`
+	Unhealthy string `json:"unhealthy,omitempty"`
+	Paused    string `json:"paused,omitempty"`
+}
+
+type Theme struct {
+	// Base is the built-in theme used for styles which are not set
+	Base string `json:"base,omitempty"`
+
+	Text      Style  `json:"text"`      // help and modal text
+	Muted     Style  `json:"muted"`     // hints and hidden columns
+	Selected  Style  `json:"selected"`  // focused row
+	Match     Style  `json:"match"`     // search matches
+	Unhealthy Style  `json:"unhealthy"` // unhealthy resources
+	Paused    Style  `json:"paused"`    // paused resources
+	Error     Style  `json:"error"`     // error messages
+	Accent    Style  `json:"accent"`    // loading spinner
+	Status    Style  `json:"status"`    // status bar prompt
+	Info      Style  `json:"info"`      // status bar messages
+	Neutral   Style  `json:"neutral"`   // status bar path and indicators
+	Alert     Style  `json:"alert"`     // status bar errors
+	Glyphs    Glyphs `json:"glyphs"`
+}
+
+var defaultGlyphs = Glyphs{Healthy: "✓", Unhealthy: "✗", Paused: "‖"}
+
+var builtin = map[string]Theme{
+	Dark: {
+		Text:      Style{Foreground: "#eeeeee"},
+		Muted:     Style{Foreground: "8"},
+		Selected:  Style{Foreground: "0", Background: "7"},
+		Match:     Style{Foreground: "15", Background: "1", Bold: true},
+		Unhealthy: Style{Foreground: "1"},
+		Paused:    Style{Foreground: "3"},
+		Error:     Style{Foreground: "1"},
+		Accent:    Style{Foreground: "205"},
+		Status:    Style{Foreground: "7", Background: "5"},
+		Info:      Style{Foreground: "0", Background: "4"},
+		Neutral:   Style{Foreground: "7", Background: "8"},
+		Alert:     Style{Foreground: "7", Background: "1"},
+		Glyphs:    defaultGlyphs,
+	},
+	Light: {
+		Text:      Style{Foreground: "#333333"},
+		Muted:     Style{Foreground: "244"},
+		Selected:  Style{Foreground: "15", Background: "0"},
+		Match:     Style{Foreground: "15", Background: "1", Bold: true},
+		Unhealthy: Style{Foreground: "124"},
+		Paused:    Style{Foreground: "130"},
+		Error:     Style{Foreground: "124"},
+		Accent:    Style{Foreground: "161"},
+		Status:    Style{Foreground: "15", Background: "5"},
+		Info:      Style{Foreground: "15", Background: "4"},
+		Neutral:   Style{Foreground: "0", Background: "252"},
+		Alert:     Style{Foreground: "15", Background: "1"},
+		Glyphs:    defaultGlyphs,
+	},
+	HighContrast: {
+		Text:      Style{Foreground: "15"},
+		Muted:     Style{Foreground: "7"},
+		Selected:  Style{Foreground: "0", Background: "11", Bold: true},
+		Match:     Style{Foreground: "0", Background: "14", Bold: true, Underline: true},
+		Unhealthy: Style{Foreground: "9", Bold: true},
+		Paused:    Style{Foreground: "11"},
+		Error:     Style{Foreground: "9", Bold: true},
+		Accent:    Style{Foreground: "14"},
+		Status:    Style{Foreground: "0", Background: "15"},
+		Info:      Style{Foreground: "0", Background: "14"},
+		Neutral:   Style{Foreground: "15", Background: "0"},
+		Alert:     Style{Foreground: "0", Background: "9"},
+		Glyphs:    defaultGlyphs,
+	},
+	// Mono relies on text attributes only, for terminals without colours
+	Mono: {
+		Selected:  Style{Reverse: true},
+		Match:     Style{Bold: true, Underline: true},
+		Unhealthy: Style{Bold: true},
+		Error:     Style{Bold: true},
+		Glyphs:    defaultGlyphs,
+	},
+}
+
+// Default returns the dark theme, which components use by default.
+func Default() Theme { return builtin[Dark] }
+
+// Names returns the built-in themes, followed by the custom ones.
+func Names(custom map[string]Theme) []string {
+	names := []string{Auto, Dark, Light, HighContrast, Mono}
+	for _, name := range slices.Sorted(maps.Keys(custom)) {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Get returns a built-in or custom theme by name. Custom themes are based on
+// their Base theme (dark by default), while `auto` is mono when NO_COLOR is
+// set, otherwise dark or light depending on the terminal background.
+func Get(name string, custom map[string]Theme) (Theme, error) {
+	if name == "" || name == Auto {
+		name = auto()
+	}
+
+	t, ok := custom[name]
+	if !ok {
+		b, ok := builtin[name]
+		if !ok {
+			return Theme{}, &ErrUnknownTheme{Name: name, Available: Names(custom)}
+		}
+		return b, nil
+	}
+
+	if t.Base == "" {
+		t.Base = Dark
+	}
+	base, ok := builtin[t.Base]
+	if !ok {
+		return Theme{}, &ErrUnknownTheme{Name: t.Base, Available: slices.Sorted(maps.Keys(builtin))}
+	}
+	return merge(base, t), nil
+}
+
+// IsBuiltin returns if a theme is built-in, which custom themes can be based on.
+func IsBuiltin(name string) bool {
+	_, ok := builtin[name]
+	return ok
+}
+
+func auto() string {
+	if os.Getenv("NO_COLOR") != "" {
+		return Mono
+	}
+	if !lipgloss.HasDarkBackground() {
+		return Light
+	}
+	return Dark
+}
+
+// merge overrides the base styles and glyphs with the ones set in t.
+func merge(base, t Theme) Theme {
+	styles := func(th *Theme) []*Style {
+		return []*Style{
+			&th.Text, &th.Muted, &th.Selected, &th.Match, &th.Unhealthy, &th.Paused,
+			&th.Error, &th.Accent, &th.Status, &th.Info, &th.Neutral, &th.Alert,
+		}
+	}
+	from := styles(&t)
+	for i, s := range styles(&base) {
+		if *from[i] != (Style{}) {
+			*s = *from[i]
+		}
+	}
+
+	glyphs := []*string{&base.Glyphs.Healthy, &base.Glyphs.Unhealthy, &base.Glyphs.Paused}
+	for i, g := range []string{t.Glyphs.Healthy, t.Glyphs.Unhealthy, t.Glyphs.Paused} {
+		if g != "" {
+			*glyphs[i] = g
+		}
+	}
+	base.Base = t.Base
+	return base
+}
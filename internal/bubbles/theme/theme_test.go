@@ -0,0 +1,99 @@
+package theme
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGet(t *testing.T) {
+	custom := map[string]Theme{
+		"solarized": {
+			Base:      Light,
+			Unhealthy: Style{Foreground: "#dc322f"},
+			Glyphs:    Glyphs{Unhealthy: "x"},
+		},
+		"plain":  {Selected: Style{Reverse: true}},
+		"broken": {Base: "solarized"},
+	}
+
+	type args struct {
+		name string
+	}
+
+	type want struct {
+		theme Theme
+		err   bool
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Builtin": {
+			reason: "Should return built-in themes",
+			args:   args{name: HighContrast},
+			want:   want{theme: builtin[HighContrast]},
+		},
+		"Custom": {
+			reason: "Should override the base theme with the custom styles and glyphs",
+			args:   args{name: "solarized"},
+			want: want{theme: func() Theme {
+				th := builtin[Light]
+				th.Base = Light
+				th.Unhealthy = Style{Foreground: "#dc322f"}
+				th.Glyphs.Unhealthy = "x"
+				return th
+			}()},
+		},
+		"DefaultBase": {
+			reason: "Should base custom themes on the dark theme by default",
+			args:   args{name: "plain"},
+			want: want{theme: func() Theme {
+				th := builtin[Dark]
+				th.Base = Dark
+				th.Selected = Style{Reverse: true}
+				return th
+			}()},
+		},
+		"CustomBase": {
+			reason: "Should only base custom themes on built-in ones",
+			args:   args{name: "broken"},
+			want:   want{err: true},
+		},
+		"Unknown": {
+			reason: "Should fail on themes which do not exist",
+			args:   args{name: "nord"},
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			th, err := Get(tc.args.name, custom)
+			if tc.want.err {
+				var themeErr *ErrUnknownTheme
+				if !errors.As(err, &themeErr) {
+					t.Fatalf("\n%s\nGet(%q): want ErrUnknownTheme, got %v", tc.reason, tc.args.name, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nGet(%q): unexpected error: %v", tc.reason, tc.args.name, err)
+			}
+			if th != tc.want.theme {
+				t.Errorf("\n%s\nGet(%q): want %+v, got %+v", tc.reason, tc.args.name, tc.want.theme, th)
+			}
+		})
+	}
+}
+
+func TestGetAuto(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+	th, err := Get(Auto, nil)
+	if err != nil {
+		t.Fatalf("Get(%q): unexpected error: %v", Auto, err)
+	}
+	if th != builtin[Mono] {
+		t.Errorf("Get(%q): want the mono theme when NO_COLOR is set, got %+v", Auto, th)
+	}
+}
@@ -0,0 +1,164 @@
+// Package config loads user settings from `$XDG_CONFIG_HOME/xpdig/config.yaml`.
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/brunoluiz/xpdig/internal/bubbles/theme"
+	"github.com/brunoluiz/xpdig/internal/xplane/column"
+	"sigs.k8s.io/yaml"
+)
+
+// Config is the user configuration file.
+type Config struct {
+	Columns []Column `json:"columns,omitempty"`
+
+	// Pager and Editor are used instead of $PAGER and $EDITOR, if set
+	Pager  string `json:"pager,omitempty"`
+	Editor string `json:"editor,omitempty"`
+
+	// Keys remaps keybindings, by component and action (eg: navigator.describe)
+	Keys map[string]map[string][]string `json:"keys,omitempty"`
+
+	// Themes are custom themes, selected with the `theme` flag
+	Themes map[string]theme.Theme `json:"themes,omitempty"`
+
+	// Defaults are used for flags which are not set, followed by the ones of
+	// the selected profile and of the Kubernetes context being used
+	Defaults Flags            `json:"defaults,omitempty"`
+	Profiles map[string]Flags `json:"profiles,omitempty"`
+	Contexts map[string]Flags `json:"contexts,omitempty"`
+}
+
+// Flags are flag values keyed by the flag name, eg: `watch-interval: 10s`.
+type Flags map[string]any
+
+// ErrInvalid is returned when a config field is invalid.
+type ErrInvalid struct {
+	Field string
+	Msg   string
+}
+
+func (e *ErrInvalid) Error() string {
+	return fmt.Sprintf("invalid config at '%s': %s", e.Field, e.Msg)
+}
+
+// ErrUnknownProfile is returned when selecting a profile which is not configured.
+type ErrUnknownProfile struct {
+	Name      string
+	Available []string
+}
+
+func (e *ErrUnknownProfile) Error() string {
+	if len(e.Available) == 0 {
+		return fmt.Sprintf("unknown profile '%s' (no profiles are configured)", e.Name)
+	}
+	return fmt.Sprintf("unknown profile '%s' (available: %s)", e.Name, strings.Join(e.Available, ", "))
+}
+
+// Column is a user-defined column, see column.Column.
+type Column struct {
+	Title string   `json:"title"`
+	Path  string   `json:"path"`
+	Width int      `json:"width,omitempty"`
+	Kinds []string `json:"kinds,omitempty"`
+}
+
+// DefaultPath returns the config file path, following the XDG spec.
+func DefaultPath() string {
+	dir := os.Getenv("XDG_CONFIG_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".config")
+	}
+	return filepath.Join(dir, "xpdig", "config.yaml")
+}
+
+// Load reads the config file at path. Missing files are the same as empty ones.
+func Load(path string) (Config, error) {
+	cfg := Config{}
+	if path == "" {
+		return cfg, nil
+	}
+
+	b, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+
+	if err := yaml.UnmarshalStrict(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("invalid config '%s': %w", path, err)
+	}
+	if _, err := cfg.GetColumns(); err != nil {
+		return cfg, fmt.Errorf("invalid config '%s': %w", path, err)
+	}
+	for _, name := range slices.Sorted(maps.Keys(cfg.Themes)) {
+		if base := cfg.Themes[name].Base; base != "" && !theme.IsBuiltin(base) {
+			return cfg, &ErrInvalid{Field: "themes." + name + ".base", Msg: fmt.Sprintf("unknown built-in theme '%s'", base)}
+		}
+	}
+	return cfg, nil
+}
+
+// Validate checks the flags of every section, with validate returning an
+// error if a flag does not exist or its value is invalid.
+func (c Config) Validate(validate func(name string, value any) error) error {
+	sections := map[string]Flags{"defaults": c.Defaults}
+	for name, flags := range c.Profiles {
+		sections["profiles."+name] = flags
+	}
+	for name, flags := range c.Contexts {
+		sections["contexts."+name] = flags
+	}
+
+	for _, section := range slices.Sorted(maps.Keys(sections)) {
+		flags := sections[section]
+		for _, name := range slices.Sorted(maps.Keys(flags)) {
+			if err := validate(name, flags[name]); err != nil {
+				return &ErrInvalid{Field: section + "." + name, Msg: err.Error()}
+			}
+		}
+	}
+	return nil
+}
+
+// Resolve merges the defaults with the flags of a profile, if set.
+func (c Config) Resolve(profile string) (Flags, error) {
+	flags := Flags{}
+	maps.Copy(flags, c.Defaults)
+	if profile == "" {
+		return flags, nil
+	}
+
+	p, ok := c.Profiles[profile]
+	if !ok {
+		return nil, &ErrUnknownProfile{Name: profile, Available: slices.Sorted(maps.Keys(c.Profiles))}
+	}
+	maps.Copy(flags, p)
+	return flags, nil
+}
+
+// GetColumns compiles the user-defined columns.
+func (c Config) GetColumns() ([]*column.Column, error) {
+	cols := []*column.Column{}
+	for _, cc := range c.Columns {
+		col, err := column.New(cc.Title, cc.Path, cc.Width, cc.Kinds)
+		if err != nil {
+			return nil, err
+		}
+		cols = append(cols, col)
+	}
+	return cols, nil
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	cfg := Config{
+		Defaults: Flags{"watch": false, "watch-interval": "5s"},
+		Profiles: map[string]Flags{
+			"prod": {"watch": true, "context": "prod"},
+		},
+	}
+
+	type args struct {
+		profile string
+	}
+
+	type want struct {
+		flags Flags
+		err   bool
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoProfile": {
+			reason: "Should only return the defaults",
+			args:   args{},
+			want:   want{flags: Flags{"watch": false, "watch-interval": "5s"}},
+		},
+		"Profile": {
+			reason: "Should override the defaults with the profile flags",
+			args:   args{profile: "prod"},
+			want:   want{flags: Flags{"watch": true, "watch-interval": "5s", "context": "prod"}},
+		},
+		"UnknownProfile": {
+			reason: "Should fail on profiles which are not configured",
+			args:   args{profile: "staging"},
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			flags, err := cfg.Resolve(tc.args.profile)
+			if tc.want.err {
+				var profileErr *ErrUnknownProfile
+				if !errors.As(err, &profileErr) {
+					t.Fatalf("\n%s\nResolve(%q): want ErrUnknownProfile, got %v", tc.reason, tc.args.profile, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nResolve(%q): unexpected error: %v", tc.reason, tc.args.profile, err)
+			}
+			if !maps.Equal(tc.want.flags, flags) {
+				t.Errorf("\n%s\nResolve(%q): want %v, got %v", tc.reason, tc.args.profile, tc.want.flags, flags)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	known := func(name string, _ any) error {
+		if name != "watch" {
+			return fmt.Errorf("unknown flag '%s'", name)
+		}
+		return nil
+	}
+
+	type args struct {
+		cfg Config
+	}
+
+	type want struct {
+		field string
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Valid": {
+			reason: "Should accept known flags in every section",
+			args: args{cfg: Config{
+				Defaults: Flags{"watch": true},
+				Profiles: map[string]Flags{"prod": {"watch": true}},
+				Contexts: map[string]Flags{"prod": {"watch": true}},
+			}},
+			want: want{},
+		},
+		"InvalidProfile": {
+			reason: "Should point out which profile flag is invalid",
+			args: args{cfg: Config{
+				Profiles: map[string]Flags{"prod": {"watch": true, "colour": "red"}},
+			}},
+			want: want{field: "profiles.prod.colour"},
+		},
+		"InvalidContext": {
+			reason: "Should point out which context flag is invalid",
+			args: args{cfg: Config{
+				Contexts: map[string]Flags{"prod": {"readonly": true}},
+			}},
+			want: want{field: "contexts.prod.readonly"},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tc.args.cfg.Validate(known)
+			if tc.want.field == "" {
+				if err != nil {
+					t.Fatalf("\n%s\nValidate(): unexpected error: %v", tc.reason, err)
+				}
+				return
+			}
+
+			var invalidErr *ErrInvalid
+			if !errors.As(err, &invalidErr) {
+				t.Fatalf("\n%s\nValidate(): want ErrInvalid, got %v", tc.reason, err)
+			}
+			if invalidErr.Field != tc.want.field {
+				t.Errorf("\n%s\nValidate(): want field %q, got %q", tc.reason, tc.want.field, invalidErr.Field)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"sigs.k8s.io/yaml"
+)
+
+type LayoutMode string
+
+const (
+	LayoutModeShort LayoutMode = "short"
+	LayoutModeWide  LayoutMode = "wide"
+)
+
+// Layouts are the column layouts chosen at runtime, by layout type (eg: resource, package).
+type Layouts map[string]Layout
+
+// Layout is the chosen mode of a layout type, plus how its columns were
+// customised in each mode.
+type Layout struct {
+	Mode    LayoutMode                    `json:"mode,omitempty"`
+	Columns map[LayoutMode][]LayoutColumn `json:"columns,omitempty"`
+}
+
+type LayoutColumn struct {
+	Title  string `json:"title"`
+	Width  int    `json:"width,omitempty"`
+	Hidden bool   `json:"hidden,omitempty"`
+}
+
+// LayoutFile stores layouts in a YAML file.
+type LayoutFile string
+
+// DefaultLayoutPath returns where layouts are stored, following the XDG spec.
+func DefaultLayoutPath() string {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "xpdig", "layouts.yaml")
+}
+
+// Load reads the stored layouts. Missing files are the same as empty ones.
+func (f LayoutFile) Load() (Layouts, error) {
+	layouts := Layouts{}
+	if f == "" {
+		return layouts, nil
+	}
+
+	b, err := os.ReadFile(string(f))
+	if errors.Is(err, os.ErrNotExist) {
+		return layouts, nil
+	}
+	if err != nil {
+		return layouts, err
+	}
+
+	if err := yaml.Unmarshal(b, &layouts); err != nil {
+		return Layouts{}, fmt.Errorf("invalid layouts '%s': %w", f, err)
+	}
+	return layouts, nil
+}
+
+func (f LayoutFile) Save(layouts Layouts) error {
+	if f == "" {
+		return nil
+	}
+
+	b, err := yaml.Marshal(layouts)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(string(f)), 0o750); err != nil {
+		return err
+	}
+	return os.WriteFile(string(f), b, 0o600)
+}
@@ -0,0 +1,116 @@
+// Package column evaluates user-defined columns, extracting values from
+// resources through JSONPath expressions (eg: `.spec.forProvider.region`).
+package column
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// DefaultWidth is used by columns without an explicit width.
+const DefaultWidth = 20
+
+// ErrInvalidSpec is returned for column specs not in the `TITLE[@Kind.group|...]=jsonpath` format.
+type ErrInvalidSpec struct {
+	Spec string
+	Msg  string
+}
+
+func (e *ErrInvalidSpec) Error() string {
+	return fmt.Sprintf("invalid column '%s': %s", e.Spec, e.Msg)
+}
+
+// Column is a user-defined column, optionally scoped to some kinds.
+type Column struct {
+	Title string
+	Path  string
+	Width int
+	// Kinds limits which resources get a value, in `Kind`, `Kind.group` or
+	// `Kind.version.group` formats. An empty list means all resources.
+	Kinds []string
+
+	jp    *jsonpath.JSONPath
+	kinds []scope
+}
+
+type scope struct {
+	gvk *schema.GroupVersionKind
+	gk  schema.GroupKind
+}
+
+// New compiles a column, validating its JSONPath expression. The expression
+// braces are optional, so `.spec.region` and `{.spec.region}` are the same.
+func New(title, path string, width int, kinds []string) (*Column, error) {
+	title = strings.TrimSpace(title)
+	if title == "" {
+		return nil, &ErrInvalidSpec{Spec: title + "=" + path, Msg: "title is required"}
+	}
+
+	tmpl := strings.TrimSpace(path)
+	if !strings.HasPrefix(tmpl, "{") {
+		tmpl = "{" + tmpl + "}"
+	}
+
+	jp := jsonpath.New(title).AllowMissingKeys(true)
+	if err := jp.Parse(tmpl); err != nil {
+		return nil, &ErrInvalidSpec{Spec: title + "=" + path, Msg: err.Error()}
+	}
+
+	if width <= 0 {
+		width = max(DefaultWidth, len(title))
+	}
+
+	c := &Column{Title: title, Path: path, Width: width, Kinds: kinds, jp: jp}
+	for _, k := range kinds {
+		gvk, gk := schema.ParseKindArg(strings.TrimSpace(k))
+		c.kinds = append(c.kinds, scope{gvk: gvk, gk: gk})
+	}
+	return c, nil
+}
+
+// Parse compiles a column from a `TITLE[@Kind.group|...]=jsonpath` spec, eg:
+// `REGION@Bucket.s3.aws.upbound.io|Instance=.spec.forProvider.region`.
+func Parse(spec string) (*Column, error) {
+	head, path, ok := strings.Cut(spec, "=")
+	if !ok || strings.TrimSpace(path) == "" {
+		return nil, &ErrInvalidSpec{Spec: spec, Msg: "must be in the TITLE[@Kind.group|...]=jsonpath format"}
+	}
+
+	title, scoped, _ := strings.Cut(head, "@")
+	var kinds []string
+	if scoped != "" {
+		kinds = strings.Split(scoped, "|")
+	}
+	return New(title, path, 0, kinds)
+}
+
+// Matches reports if the column applies to resources of a given kind.
+func (c *Column) Matches(gvk schema.GroupVersionKind) bool {
+	if len(c.kinds) == 0 {
+		return true
+	}
+
+	for _, s := range c.kinds {
+		if s.gvk != nil && *s.gvk == gvk {
+			return true
+		}
+		if s.gk.Kind == gvk.Kind && (s.gk.Group == "" || s.gk.Group == gvk.Group) {
+			return true
+		}
+	}
+	return false
+}
+
+// Value evaluates the column against an object, returning `-` if there
+// is no value at its path.
+func (c *Column) Value(obj map[string]any) string {
+	var buf bytes.Buffer
+	if err := c.jp.Execute(&buf, obj); err != nil || buf.Len() == 0 {
+		return "-"
+	}
+	return strings.ReplaceAll(buf.String(), "\n", " ")
+}
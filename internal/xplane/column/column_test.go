@@ -0,0 +1,107 @@
+package column
+
+import (
+	"errors"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+func TestParse(t *testing.T) {
+	bucket := schema.GroupVersionKind{Group: "s3.aws.upbound.io", Version: "v1beta1", Kind: "Bucket"}
+	instance := schema.GroupVersionKind{Group: "ec2.aws.upbound.io", Version: "v1beta1", Kind: "Instance"}
+	obj := map[string]any{
+		"metadata": map[string]any{"labels": map[string]any{"owner": "payments"}},
+		"spec":     map[string]any{"forProvider": map[string]any{"region": "eu-west-1"}},
+	}
+
+	type args struct {
+		spec string
+	}
+
+	type want struct {
+		title   string
+		value   string
+		matches []bool
+		err     bool
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Unscoped": {
+			reason: "Should evaluate the path against all kinds",
+			args:   args{spec: "REGION=.spec.forProvider.region"},
+			want:   want{title: "REGION", value: "eu-west-1", matches: []bool{true, true}},
+		},
+		"Braces": {
+			reason: "Should accept paths wrapped in braces",
+			args:   args{spec: "OWNER={.metadata.labels.owner}"},
+			want:   want{title: "OWNER", value: "payments", matches: []bool{true, true}},
+		},
+		"Missing": {
+			reason: "Should render missing values as a dash",
+			args:   args{spec: "TYPE=.spec.forProvider.instanceType"},
+			want:   want{title: "TYPE", value: "-", matches: []bool{true, true}},
+		},
+		"ScopedKind": {
+			reason: "Should only match scoped kinds",
+			args:   args{spec: "REGION@Bucket=.spec.forProvider.region"},
+			want:   want{title: "REGION", value: "eu-west-1", matches: []bool{true, false}},
+		},
+		"ScopedGroupKinds": {
+			reason: "Should match any of the scoped kinds, with their groups",
+			args:   args{spec: "REGION@Bucket.sqs.aws.upbound.io|Instance.ec2.aws.upbound.io=.spec.forProvider.region"},
+			want:   want{title: "REGION", value: "eu-west-1", matches: []bool{false, true}},
+		},
+		"ScopedVersionKind": {
+			reason: "Should match scoped kinds with versions",
+			args:   args{spec: "REGION@Bucket.v1beta1.s3.aws.upbound.io=.spec.forProvider.region"},
+			want:   want{title: "REGION", value: "eu-west-1", matches: []bool{true, false}},
+		},
+		"NoPath": {
+			reason: "Should fail without a path",
+			args:   args{spec: "REGION"},
+			want:   want{err: true},
+		},
+		"NoTitle": {
+			reason: "Should fail without a title",
+			args:   args{spec: "=.spec.region"},
+			want:   want{err: true},
+		},
+		"InvalidPath": {
+			reason: "Should fail on invalid JSONPath expressions",
+			args:   args{spec: "REGION=.spec[.region"},
+			want:   want{err: true},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			c, err := Parse(tc.args.spec)
+			if tc.want.err {
+				var specErr *ErrInvalidSpec
+				if !errors.As(err, &specErr) {
+					t.Fatalf("\n%s\nParse(%q): want ErrInvalidSpec, got %v", tc.reason, tc.args.spec, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("\n%s\nParse(%q): unexpected error: %v", tc.reason, tc.args.spec, err)
+			}
+
+			if c.Title != tc.want.title {
+				t.Errorf("\n%s\nTitle: want %q, got %q", tc.reason, tc.want.title, c.Title)
+			}
+			if got := c.Value(obj); got != tc.want.value {
+				t.Errorf("\n%s\nValue(): want %q, got %q", tc.reason, tc.want.value, got)
+			}
+			for i, gvk := range []schema.GroupVersionKind{bucket, instance} {
+				if got := c.Matches(gvk); got != tc.want.matches[i] {
+					t.Errorf("\n%s\nMatches(%s): want %t, got %t", tc.reason, gvk, tc.want.matches[i], got)
+				}
+			}
+		})
+	}
+}
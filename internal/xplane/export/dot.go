@@ -0,0 +1,48 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/brunoluiz/xpdig/internal/xplane"
+)
+
+// DOT renders the trace as a Graphviz digraph, with one cluster per API group.
+func DOT(w io.Writer, r *xplane.Resource) error {
+	nodes := flatten(r)
+	groups, byGroup := groupNodes(nodes)
+
+	b := &strings.Builder{}
+	b.WriteString("digraph xpdig {\n")
+	b.WriteString("  rankdir=LR;\n")
+	b.WriteString("  node [shape=box, style=\"rounded,filled\", fontname=\"Helvetica\"];\n")
+
+	for i, g := range groups {
+		fmt.Fprintf(b, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(b, "    label=%s;\n", dotQuote(g))
+		for _, n := range byGroup[g] {
+			label := strings.Join([]string{n.kind, n.name, n.status}, "\n")
+			fmt.Fprintf(b, "    %s [label=%s, fillcolor=%s];\n", n.id, dotQuote(label), dotQuote(fillByHealth[n.health]))
+		}
+		b.WriteString("  }\n")
+	}
+
+	for _, n := range nodes {
+		if n.parent == "" {
+			continue
+		}
+		fmt.Fprintf(b, "  %s -> %s;\n", n.parent, n.id)
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func dotQuote(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	s = strings.ReplaceAll(s, `"`, `\"`)
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	return `"` + s + `"`
+}
@@ -0,0 +1,169 @@
+// Package export renders crossplane traces into formats that can be shared
+// outside of the terminal, such as diagrams.
+package export
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/brunoluiz/xpdig/internal/xplane"
+)
+
+type Format string
+
+const (
+	FormatDOT     Format = "dot"
+	FormatMermaid Format = "mermaid"
+	FormatHTML    Format = "html"
+)
+
+// Meta information about the capture, for formats that embed it.
+type Meta struct {
+	CapturedAt time.Time
+	Context    string
+}
+
+// maxStatusLen avoids huge error messages blowing up diagram nodes.
+const maxStatusLen = 60
+
+var fillByHealth = map[xplane.Health]string{
+	xplane.HealthOk:        "#b7e1a1",
+	xplane.HealthUnhealthy: "#f4a6a6",
+	xplane.HealthPaused:    "#f9e79f",
+	xplane.HealthDeleting:  "#d5d8dc",
+}
+
+type ErrUnknownFormat struct {
+	Format string
+}
+
+func (e *ErrUnknownFormat) Error() string {
+	return fmt.Sprintf("unknown export format '%s'", e.Format)
+}
+
+type writerFn func(w io.Writer, r *xplane.Resource, meta Meta) error
+
+var writers = map[Format]writerFn{
+	FormatDOT:     func(w io.Writer, r *xplane.Resource, _ Meta) error { return DOT(w, r) },
+	FormatMermaid: func(w io.Writer, r *xplane.Resource, _ Meta) error { return Mermaid(w, r) },
+	FormatHTML:    HTML,
+}
+
+// Write renders the trace on the requested format.
+func Write(w io.Writer, f Format, r *xplane.Resource, meta Meta) error {
+	fn, ok := writers[f]
+	if !ok {
+		return &ErrUnknownFormat{Format: string(f)}
+	}
+	return fn(w, r, meta)
+}
+
+// WriteFile renders the trace into dst, with `-` meaning stdout. Mermaid
+// diagrams written to markdown files are wrapped in a code fence, so they
+// are rendered straight away by GitHub and similar.
+func WriteFile(dst string, f Format, r *xplane.Resource, meta Meta) (err error) {
+	if dst == "-" {
+		return Write(os.Stdout, f, r, meta)
+	}
+
+	out, err := os.Create(filepath.Clean(dst))
+	if err != nil {
+		return fmt.Errorf("failed to create export file: %w", err)
+	}
+	defer func() { err = errors.Join(err, out.Close()) }()
+
+	fenced := f == FormatMermaid && strings.EqualFold(filepath.Ext(dst), ".md")
+	if fenced {
+		if _, err := fmt.Fprintln(out, "```mermaid"); err != nil {
+			return err
+		}
+	}
+	if err := Write(out, f, r, meta); err != nil {
+		return err
+	}
+	if fenced {
+		if _, err := fmt.Fprintln(out, "```"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Filename returns a default file name for the trace export.
+func Filename(f Format, r *xplane.Resource) string {
+	ext := string(f)
+	if f == FormatMermaid {
+		ext = "mmd"
+	}
+	return strings.ToLower(fmt.Sprintf("%s-%s.%s", r.Unstructured.GetKind(), r.Unstructured.GetName(), ext))
+}
+
+// node flattened representation of a trace node, shared by all diagram formats.
+type node struct {
+	id     string
+	parent string
+	kind   string
+	name   string
+	group  string
+	status string
+	health xplane.Health
+}
+
+func flatten(r *xplane.Resource) []node {
+	pkg := xplane.IsPkg(r.Unstructured.GroupVersionKind().GroupKind())
+	nodes := []node{}
+
+	var walk func(v *xplane.Resource, parent string)
+	walk = func(v *xplane.Resource, parent string) {
+		status, _ := xplane.GetStatus(v, pkg)
+		n := node{
+			id:     fmt.Sprintf("n%d", len(nodes)),
+			parent: parent,
+			kind:   v.Unstructured.GetKind(),
+			name:   v.Unstructured.GetName(),
+			group:  v.Unstructured.GroupVersionKind().Group,
+			status: truncate(status, maxStatusLen),
+			health: xplane.GetHealth(v, pkg),
+		}
+		if n.group == "" {
+			n.group = "core"
+		}
+		if n.status == "" {
+			n.status = "-"
+		}
+		nodes = append(nodes, n)
+
+		for _, c := range v.Children {
+			walk(c, n.id)
+		}
+	}
+	walk(r, "")
+
+	return nodes
+}
+
+// groupNodes returns nodes grouped by API group, keeping the order they first appear.
+func groupNodes(nodes []node) ([]string, map[string][]node) {
+	groups := []string{}
+	byGroup := map[string][]node{}
+	for _, n := range nodes {
+		if _, ok := byGroup[n.group]; !ok {
+			groups = append(groups, n.group)
+		}
+		byGroup[n.group] = append(byGroup[n.group], n)
+	}
+	return groups, byGroup
+}
+
+func truncate(s string, n int) string {
+	r := []rune(s)
+	if len(r) <= n {
+		return s
+	}
+	return string(r[:n-1]) + "…"
+}
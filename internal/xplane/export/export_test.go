@@ -0,0 +1,95 @@
+package export
+
+import (
+	"bytes"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/brunoluiz/xpdig/internal/xplane"
+)
+
+func TestWrite(t *testing.T) {
+	type args struct {
+		format Format
+	}
+
+	type want struct {
+		contains []string
+		err      bool
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"DOT": {
+			reason: "Should render clusters, health fill colours and edges",
+			args:   args{format: FormatDOT},
+			want: want{
+				contains: []string{
+					`subgraph cluster_0 {`,
+					`label="test.cloud";`,
+					`n0 [label="ObjectStorage\ntest-resource\n-", fillcolor="#b7e1a1"];`,
+					`fillcolor="#f4a6a6"`,
+					`n5 -> n6;`,
+				},
+			},
+		},
+		"Mermaid": {
+			reason: "Should render subgraphs, health classes and edges",
+			args:   args{format: FormatMermaid},
+			want: want{
+				contains: []string{
+					`subgraph g0["test.cloud"]`,
+					`n0["ObjectStorage<br/>test-resource<br/>-"]`,
+					`class n3 unhealthy`,
+					`n5 --> n6`,
+				},
+			},
+		},
+		"HTML": {
+			reason: "Should render a report with capture metadata, YAML and conditions",
+			args:   args{format: FormatHTML},
+			want: want{
+				contains: []string{
+					`<dt>Kube context</dt><dd>unknown</dd>`,
+					`<span class="health unhealthy">unhealthy</span>`,
+					`kind: ObjectStorage`,
+					`<td>SomethingWrongHappened</td>`,
+				},
+			},
+		},
+		"Unknown": {
+			reason: "Should fail for unknown formats",
+			args:   args{format: Format("svg")},
+			want:   want{err: true},
+		},
+	}
+
+	f, err := os.Open("../../../fixture/crossplane-resource.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	trace, err := xplane.Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			b := &bytes.Buffer{}
+			err := Write(b, tc.args.format, trace, Meta{})
+			if (err != nil) != tc.want.err {
+				t.Fatalf("\n%s\nWrite(...): unexpected error: %v", tc.reason, err)
+			}
+			for _, s := range tc.want.contains {
+				if !strings.Contains(b.String(), s) {
+					t.Errorf("\n%s\nWrite(...): missing %q in:\n%s", tc.reason, s, b.String())
+				}
+			}
+		})
+	}
+}
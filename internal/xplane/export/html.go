@@ -0,0 +1,99 @@
+package export
+
+import (
+	_ "embed"
+	"fmt"
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/brunoluiz/xpdig/internal/xplane"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	"sigs.k8s.io/yaml"
+)
+
+//go:embed html.tmpl
+var htmlTmpl string
+
+var htmlReport = template.Must(template.New("report").Funcs(template.FuncMap{
+	"time": func(t time.Time) string {
+		if t.IsZero() {
+			return "-"
+		}
+		return t.Format(time.RFC3339)
+	},
+}).Parse(htmlTmpl))
+
+type htmlNode struct {
+	Kind       string
+	Name       string
+	Group      string
+	Namespace  string
+	Status     string
+	Health     xplane.Health
+	YAML       string
+	Conditions []xpv1.Condition
+	Children   []htmlNode
+}
+
+type htmlData struct {
+	Title      string
+	CapturedAt string
+	Context    string
+	Root       htmlNode
+}
+
+// HTML renders a self-contained report, with a collapsible tree and every
+// node YAML and conditions embedded. It does not depend on external assets,
+// so it can be browsed offline by people without cluster access.
+func HTML(w io.Writer, r *xplane.Resource, meta Meta) error {
+	pkg := xplane.IsPkg(r.Unstructured.GroupVersionKind().GroupKind())
+	root, err := toHTMLNode(r, pkg)
+	if err != nil {
+		return err
+	}
+
+	capturedAt := meta.CapturedAt
+	if capturedAt.IsZero() {
+		capturedAt = time.Now()
+	}
+	kubeContext := meta.Context
+	if kubeContext == "" {
+		kubeContext = "unknown"
+	}
+
+	return htmlReport.Execute(w, htmlData{
+		Title:      fmt.Sprintf("%s/%s", root.Kind, root.Name),
+		CapturedAt: capturedAt.Format(time.RFC3339),
+		Context:    kubeContext,
+		Root:       root,
+	})
+}
+
+func toHTMLNode(r *xplane.Resource, pkg bool) (htmlNode, error) {
+	b, err := yaml.Marshal(r.Unstructured.Object)
+	if err != nil {
+		return htmlNode{}, fmt.Errorf("failed to encode %s/%s as YAML: %w", r.Unstructured.GetKind(), r.Unstructured.GetName(), err)
+	}
+
+	status, _ := xplane.GetStatus(r, pkg)
+	n := htmlNode{
+		Kind:       r.Unstructured.GetKind(),
+		Name:       r.Unstructured.GetName(),
+		Group:      r.Unstructured.GroupVersionKind().Group,
+		Namespace:  r.Unstructured.GetNamespace(),
+		Status:     status,
+		Health:     xplane.GetHealth(r, pkg),
+		YAML:       string(b),
+		Conditions: r.GetConditions(),
+	}
+
+	for _, c := range r.Children {
+		cn, err := toHTMLNode(c, pkg)
+		if err != nil {
+			return htmlNode{}, err
+		}
+		n.Children = append(n.Children, cn)
+	}
+	return n, nil
+}
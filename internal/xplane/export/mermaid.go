@@ -0,0 +1,57 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/brunoluiz/xpdig/internal/xplane"
+)
+
+// Mermaid renders the trace as a Mermaid flowchart, with one subgraph per API group.
+func Mermaid(w io.Writer, r *xplane.Resource) error {
+	nodes := flatten(r)
+	groups, byGroup := groupNodes(nodes)
+
+	b := &strings.Builder{}
+	b.WriteString("flowchart LR\n")
+
+	for i, g := range groups {
+		fmt.Fprintf(b, "  subgraph g%d[%s]\n", i, mermaidQuote(g))
+		for _, n := range byGroup[g] {
+			label := strings.Join([]string{mermaidEscape(n.kind), mermaidEscape(n.name), mermaidEscape(n.status)}, "<br/>")
+			fmt.Fprintf(b, "    %s[\"%s\"]\n", n.id, label)
+		}
+		b.WriteString("  end\n")
+	}
+
+	for _, n := range nodes {
+		if n.parent == "" {
+			continue
+		}
+		fmt.Fprintf(b, "  %s --> %s\n", n.parent, n.id)
+	}
+
+	for _, h := range []xplane.Health{xplane.HealthOk, xplane.HealthUnhealthy, xplane.HealthPaused, xplane.HealthDeleting} {
+		fmt.Fprintf(b, "  classDef %s fill:%s,color:#000\n", h, fillByHealth[h])
+	}
+	for _, n := range nodes {
+		fmt.Fprintf(b, "  class %s %s\n", n.id, n.health)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func mermaidQuote(s string) string {
+	return `"` + mermaidEscape(s) + `"`
+}
+
+// mermaidEscape replaces characters that break mermaid labels with their entity codes.
+func mermaidEscape(s string) string {
+	return strings.NewReplacer(
+		`"`, "#quot;",
+		"<", "#lt;",
+		">", "#gt;",
+	).Replace(s)
+}
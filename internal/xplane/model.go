@@ -23,16 +23,21 @@ type Resource struct {
 	Children     []*Resource               `json:"children,omitempty"`
 }
 
-// GetCondition of this resource.
-func (r *Resource) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
+// GetConditions returns all status conditions of this resource.
+func (r *Resource) GetConditions() []xpv1.Condition {
 	conditioned := xpv1.ConditionedStatus{}
 	// The path is directly `status` because conditions are inline.
 	if err := fieldpath.Pave(r.Unstructured.Object).GetValueInto("status", &conditioned); err != nil {
-		return xpv1.Condition{}
+		return nil
 	}
+	return conditioned.Conditions
+}
+
+// GetCondition of this resource.
+func (r *Resource) GetCondition(ct xpv1.ConditionType) xpv1.Condition {
 	// We didn't use xpv1.CondidionedStatus.GetCondition because that's defaulting the
 	// status to unknown if the condition is not found at all.
-	for _, c := range conditioned.Conditions {
+	for _, c := range r.GetConditions() {
 		if c.Type == ct {
 			return c
 		}
@@ -55,6 +60,91 @@ func IsPkg(gk schema.GroupKind) bool {
 	return xpkg.IsPackageType(gk) || xpkg.IsPackageRevisionType(gk)
 }
 
+// Health condensed state of a resource, used to decide how it should be highlighted.
+type Health string
+
+const (
+	HealthOk        Health = "ok"
+	HealthUnhealthy Health = "unhealthy"
+	HealthPaused    Health = "paused"
+	HealthDeleting  Health = "deleting"
+)
+
+// AnnotationPaused pauses the reconciliation of a resource if set to "true".
+const AnnotationPaused = "crossplane.io/paused"
+
+// AnnotationReconcileRequestedAt is set to the current time to get a resource
+// reconciled straight away, as any change to it triggers its controller.
+const AnnotationReconcileRequestedAt = "xpdig/reconcile-requested-at"
+
+// ConditionChange is a condition which transitioned between two traces.
+type ConditionChange struct {
+	Type string
+	Old  string
+	New  string
+}
+
+func (c ConditionChange) String() string {
+	return fmt.Sprintf("%s %s→%s", c.Type, mapEmptyStatusToDash(corev1.ConditionStatus(c.Old)), mapEmptyStatusToDash(corev1.ConditionStatus(c.New)))
+}
+
+// ChangedConditions returns the conditions which transitioned between two
+// traces of the same resource, including ones which went back and forth
+// (same status, but a newer transition time) and ones which were removed.
+func ChangedConditions(before, after *Resource) []ConditionChange {
+	old := map[xpv1.ConditionType]xpv1.Condition{}
+	for _, c := range before.GetConditions() {
+		old[c.Type] = c
+	}
+
+	changes := []ConditionChange{}
+	for _, c := range after.GetConditions() {
+		o, ok := old[c.Type]
+		delete(old, c.Type)
+		if ok && o.Status == c.Status && o.LastTransitionTime.Equal(&c.LastTransitionTime) {
+			continue
+		}
+		changes = append(changes, ConditionChange{Type: string(c.Type), Old: string(o.Status), New: string(c.Status)})
+	}
+	for _, c := range before.GetConditions() {
+		if _, removed := old[c.Type]; removed {
+			changes = append(changes, ConditionChange{Type: string(c.Type), Old: string(c.Status)})
+		}
+	}
+	return changes
+}
+
+// IsPaused returns true if the resource reconciliation is paused via annotation.
+func (r *Resource) IsPaused() bool {
+	return r.Unstructured.GetAnnotations()[AnnotationPaused] == "true"
+}
+
+// GetStatus returns the status message and whether it is considered ok or not.
+// Set pkg if the trace is for packages (providers, configurations and functions).
+func GetStatus(r *Resource, pkg bool) (string, bool) {
+	if pkg {
+		s := GetPkgResourceStatus(r, "")
+		return s.Status, s.Ok
+	}
+	s := GetResourceStatus(r, "")
+	return s.Status, s.Ok
+}
+
+// GetHealth returns the resource health, with deletions and pauses taking precedence.
+func GetHealth(r *Resource, pkg bool) Health {
+	_, ok := GetStatus(r, pkg)
+	switch {
+	case r.Unstructured.GetDeletionTimestamp() != nil:
+		return HealthDeleting
+	case r.IsPaused():
+		return HealthPaused
+	case !ok || r.Error != nil:
+		return HealthUnhealthy
+	default:
+		return HealthOk
+	}
+}
+
 // getResourceStatus returns a string that represents an entire row of status
 // information for the resource.
 func GetResourceStatus(r *Resource, name string) ResourceStatus {
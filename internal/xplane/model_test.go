@@ -0,0 +1,85 @@
+package xplane
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func withConditions(conditions ...map[string]any) *Resource {
+	cc := make([]any, 0, len(conditions))
+	for _, c := range conditions {
+		cc = append(cc, c)
+	}
+	return &Resource{Unstructured: unstructured.Unstructured{Object: map[string]any{
+		"status": map[string]any{"conditions": cc},
+	}}}
+}
+
+func TestChangedConditions(t *testing.T) {
+	type args struct {
+		before *Resource
+		after  *Resource
+	}
+
+	type want struct {
+		changes []ConditionChange
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Unchanged": {
+			reason: "Should return no changes if the conditions are the same",
+			args: args{
+				before: withConditions(map[string]any{"type": "Ready", "status": "False", "lastTransitionTime": "2024-01-01T00:00:00Z"}),
+				after:  withConditions(map[string]any{"type": "Ready", "status": "False", "lastTransitionTime": "2024-01-01T00:00:00Z"}),
+			},
+			want: want{changes: []ConditionChange{}},
+		},
+		"Transitioned": {
+			reason: "Should return conditions with another status, or new ones",
+			args: args{
+				before: withConditions(map[string]any{"type": "Ready", "status": "False", "lastTransitionTime": "2024-01-01T00:00:00Z"}),
+				after: withConditions(
+					map[string]any{"type": "Ready", "status": "True", "lastTransitionTime": "2024-01-01T00:01:00Z"},
+					map[string]any{"type": "Synced", "status": "True", "lastTransitionTime": "2024-01-01T00:01:00Z"},
+				),
+			},
+			want: want{changes: []ConditionChange{
+				{Type: "Ready", Old: "False", New: "True"},
+				{Type: "Synced", Old: "", New: "True"},
+			}},
+		},
+		"BackAndForth": {
+			reason: "Should return conditions with the same status, but a newer transition time",
+			args: args{
+				before: withConditions(map[string]any{"type": "Synced", "status": "False", "lastTransitionTime": "2024-01-01T00:00:00Z"}),
+				after:  withConditions(map[string]any{"type": "Synced", "status": "False", "lastTransitionTime": "2024-01-01T00:05:00Z"}),
+			},
+			want: want{changes: []ConditionChange{{Type: "Synced", Old: "False", New: "False"}}},
+		},
+		"Removed": {
+			reason: "Should return conditions which are gone, without a new status",
+			args: args{
+				before: withConditions(
+					map[string]any{"type": "Ready", "status": "True", "lastTransitionTime": "2024-01-01T00:00:00Z"},
+					map[string]any{"type": "Healthy", "status": "False", "lastTransitionTime": "2024-01-01T00:00:00Z"},
+				),
+				after: withConditions(map[string]any{"type": "Ready", "status": "True", "lastTransitionTime": "2024-01-01T00:00:00Z"}),
+			},
+			want: want{changes: []ConditionChange{{Type: "Healthy", Old: "False", New: ""}}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := ChangedConditions(tc.args.before, tc.args.after)
+			if diff := cmp.Diff(tc.want.changes, got); diff != "" {
+				t.Errorf("\n%s\nChangedConditions(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+// Package notifier detects state transitions between successive traces and
+// notifies sinks (webhooks, terminal bell etc) about them.
+package notifier
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brunoluiz/xpdig/internal/xplane"
+	"golang.org/x/time/rate"
+)
+
+type Severity string
+
+const (
+	SeverityInfo  Severity = "info"
+	SeverityError Severity = "error"
+)
+
+type ErrInvalidSeverity struct {
+	Severity string
+}
+
+func (e *ErrInvalidSeverity) Error() string {
+	return fmt.Sprintf("invalid severity '%s': must be '%s' or '%s'", e.Severity, SeverityInfo, SeverityError)
+}
+
+func ParseSeverity(s string) (Severity, error) {
+	switch Severity(strings.ToLower(s)) {
+	case SeverityInfo:
+		return SeverityInfo, nil
+	case SeverityError:
+		return SeverityError, nil
+	default:
+		return "", &ErrInvalidSeverity{Severity: s}
+	}
+}
+
+func (s Severity) level() int {
+	if s == SeverityError {
+		return 1
+	}
+	return 0
+}
+
+// State of a resource at a point in time.
+type State struct {
+	Ready  string `json:"ready"`
+	Synced string `json:"synced"`
+	Error  string `json:"error,omitempty"`
+	Ok     bool   `json:"ok"`
+}
+
+// Transition of a resource between two traces.
+type Transition struct {
+	Resource  string    `json:"resource"`
+	Kind      string    `json:"kind"`
+	Old       State     `json:"old"`
+	New       State     `json:"new"`
+	Message   string    `json:"message"`
+	Severity  Severity  `json:"severity"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Sink delivers transition notifications somewhere.
+type Sink interface {
+	Notify(ctx context.Context, t Transition) error
+}
+
+type snapshot struct {
+	kind    string
+	state   State
+	message string
+}
+
+// Notifier keeps the last seen trace state, firing notifications to all sinks
+// whenever a resource Ready, Synced or error changes.
+type Notifier struct {
+	logger   *slog.Logger
+	sinks    []Sink
+	kinds    []string
+	severity Severity
+	limiter  *rate.Limiter
+
+	mu   sync.Mutex
+	last map[string]snapshot
+}
+
+type WithOpt func(*Notifier)
+
+// WithKinds only notifies about transitions for the given kinds.
+func WithKinds(kinds []string) func(*Notifier) {
+	return func(n *Notifier) {
+		n.kinds = kinds
+	}
+}
+
+// WithMinSeverity only notifies about transitions with at least this severity.
+func WithMinSeverity(s Severity) func(*Notifier) {
+	return func(n *Notifier) {
+		n.severity = s
+	}
+}
+
+// WithRateLimit caps how many notifications are sent per minute, with any
+// excess being dropped.
+func WithRateLimit(perMinute int) func(*Notifier) {
+	return func(n *Notifier) {
+		if perMinute <= 0 {
+			n.limiter = rate.NewLimiter(rate.Inf, 0)
+			return
+		}
+		n.limiter = rate.NewLimiter(rate.Every(time.Minute/time.Duration(perMinute)), perMinute)
+	}
+}
+
+func New(logger *slog.Logger, sinks []Sink, opts ...WithOpt) *Notifier {
+	n := &Notifier{
+		logger:   logger,
+		sinks:    sinks,
+		severity: SeverityInfo,
+		limiter:  rate.NewLimiter(rate.Inf, 0),
+	}
+
+	for _, opt := range opts {
+		opt(n)
+	}
+
+	return n
+}
+
+// Observe compares the trace against the previous one, notifying about
+// transitions. The first observed trace is only used as a baseline.
+func (n *Notifier) Observe(ctx context.Context, r *xplane.Resource) error {
+	if r == nil {
+		return nil
+	}
+
+	next := map[string]snapshot{}
+	pkg := xplane.IsPkg(r.Unstructured.GroupVersionKind().GroupKind())
+	collect(r, pkg, next)
+
+	n.mu.Lock()
+	prev := n.last
+	n.last = next
+	n.mu.Unlock()
+
+	if prev == nil {
+		return nil
+	}
+
+	errs := []error{}
+	for _, t := range diff(prev, next, time.Now()) {
+		if !n.accepts(t) {
+			continue
+		}
+		if !n.limiter.Allow() {
+			n.logger.Warn("notification dropped due to rate limiting", "resource", t.Resource)
+			continue
+		}
+		for _, s := range n.sinks {
+			if err := s.Notify(ctx, t); err != nil {
+				errs = append(errs, fmt.Errorf("failed to notify about %s: %w", t.Resource, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) accepts(t Transition) bool {
+	if len(n.kinds) > 0 && !slices.ContainsFunc(n.kinds, func(k string) bool { return strings.EqualFold(k, t.Kind) }) {
+		return false
+	}
+	return t.Severity.level() >= n.severity.level()
+}
+
+func collect(r *xplane.Resource, pkg bool, out map[string]snapshot) {
+	s := snapshot{kind: r.Unstructured.GetKind()}
+	if pkg {
+		st := xplane.GetPkgResourceStatus(r, "")
+		s.state = State{Ready: st.Healthy, Synced: st.Installed, Ok: st.Ok}
+		s.message = st.Status
+	} else {
+		st := xplane.GetResourceStatus(r, "")
+		s.state = State{Ready: st.Ready, Synced: st.Synced, Ok: st.Ok}
+		s.message = st.Status
+	}
+	if r.Error != nil {
+		s.state.Error = r.Error.Error()
+		s.state.Ok = false
+	}
+	out[ID(r)] = s
+
+	for _, c := range r.Children {
+		collect(c, pkg, out)
+	}
+}
+
+func diff(prev, next map[string]snapshot, now time.Time) []Transition {
+	ids := make([]string, 0, len(next))
+	for id := range next {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	transitions := []Transition{}
+	for _, id := range ids {
+		curr := next[id]
+		old, ok := prev[id]
+		if !ok || old.state == curr.state {
+			continue
+		}
+
+		severity := SeverityInfo
+		if !curr.state.Ok {
+			severity = SeverityError
+		}
+		transitions = append(transitions, Transition{
+			Resource:  id,
+			Kind:      curr.kind,
+			Old:       old.state,
+			New:       curr.state,
+			Message:   curr.message,
+			Severity:  severity,
+			Timestamp: now,
+		})
+	}
+	return transitions
+}
+
+// ID returns the resource identity, stable between traces.
+func ID(r *xplane.Resource) string {
+	id := fmt.Sprintf("%s.%s/%s", r.Unstructured.GetKind(), r.Unstructured.GroupVersionKind().Group, r.Unstructured.GetName())
+	if ns := r.Unstructured.GetNamespace(); ns != "" {
+		return ns + "/" + id
+	}
+	return id
+}
@@ -0,0 +1,124 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"testing"
+
+	"github.com/brunoluiz/xpdig/internal/ds"
+	"github.com/brunoluiz/xpdig/internal/xplane"
+)
+
+func loadTrace(t *testing.T) *xplane.Resource {
+	t.Helper()
+	f, err := os.Open("../../../fixture/crossplane-resource.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	trace, err := xplane.Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return trace
+}
+
+func setReady(r *xplane.Resource, status string) {
+	conditions, _ := ds.GetPath[[]any](r.Unstructured.Object, "status", "conditions")
+	for _, c := range conditions {
+		cond, ok := c.(map[string]any)
+		if ok && cond["type"] == "Ready" {
+			cond["status"] = status
+			cond["reason"] = "Changed"
+		}
+	}
+}
+
+func TestNotifierObserve(t *testing.T) {
+	type args struct {
+		opts []WithOpt
+	}
+
+	type want struct {
+		transitions int
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"AllTransitions": {
+			reason: "Should notify about every transition",
+			args:   args{},
+			want:   want{transitions: 3},
+		},
+		"KindFiltered": {
+			reason: "Should ignore transitions of kinds not being watched",
+			args:   args{opts: []WithOpt{WithKinds([]string{"User"})}},
+			want:   want{transitions: 0},
+		},
+		"SeverityFiltered": {
+			reason: "Should drop transitions into healthy states when only errors are wanted",
+			args:   args{opts: []WithOpt{WithMinSeverity(SeverityError)}},
+			want:   want{transitions: 2},
+		},
+		"RateLimited": {
+			reason: "Should drop transitions once the rate limit is reached",
+			args:   args{opts: []WithOpt{WithRateLimit(1)}},
+			want:   want{transitions: 1},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			mu := sync.Mutex{}
+			received := []Transition{}
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				var tr Transition
+				if err := json.NewDecoder(r.Body).Decode(&tr); err != nil {
+					t.Errorf("invalid payload: %v", err)
+				}
+				mu.Lock()
+				received = append(received, tr)
+				mu.Unlock()
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer srv.Close()
+
+			n := New(slog.New(slog.DiscardHandler), []Sink{NewWebhook(srv.URL)}, tc.args.opts...)
+			trace := loadTrace(t)
+			if err := n.Observe(context.Background(), trace); err != nil {
+				t.Fatal(err)
+			}
+
+			// Composite and bucket go from ready to not ready
+			setReady(trace.Children[0], "False")
+			setReady(trace.Children[0].Children[0], "False")
+			if err := n.Observe(context.Background(), trace); err != nil {
+				t.Fatal(err)
+			}
+
+			// Bucket recovers, which is only informative
+			setReady(trace.Children[0].Children[0], "True")
+			if err := n.Observe(context.Background(), trace); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(received) != tc.want.transitions {
+				t.Fatalf("\n%s\nObserve(...): want %d transitions, got %d", tc.reason, tc.want.transitions, len(received))
+			}
+			if len(received) > 0 {
+				tr := received[0]
+				if tr.Kind != "Bucket" || tr.Old.Ready != "True" || tr.New.Ready != "False" || tr.Severity != SeverityError {
+					t.Errorf("\n%s\nObserve(...): unexpected transition %+v", tc.reason, tr)
+				}
+			}
+		})
+	}
+}
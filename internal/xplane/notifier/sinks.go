@@ -0,0 +1,72 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Webhook posts transitions as JSON to an HTTP endpoint.
+type Webhook struct {
+	url    string
+	client *http.Client
+}
+
+func NewWebhook(url string) *Webhook {
+	return &Webhook{url: url, client: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (w *Webhook) Notify(ctx context.Context, t Transition) error {
+	body, err := json.Marshal(t)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call webhook: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("webhook returned unexpected status %d", res.StatusCode)
+	}
+	return nil
+}
+
+// Terminal rings the terminal bell and/or sends an OSC 9 desktop notification,
+// which is supported by terminals such as iTerm2, kitty and Windows Terminal.
+type Terminal struct {
+	w       io.Writer
+	bell    bool
+	desktop bool
+}
+
+func NewTerminal(w io.Writer, bell, desktop bool) *Terminal {
+	return &Terminal{w: w, bell: bell, desktop: desktop}
+}
+
+func (t *Terminal) Notify(_ context.Context, tr Transition) error {
+	if t.desktop {
+		msg := fmt.Sprintf("xpdig: %s is now ready=%s synced=%s", tr.Resource, tr.New.Ready, tr.New.Synced)
+		if _, err := fmt.Fprintf(t.w, "\x1b]9;%s\x07", msg); err != nil {
+			return err
+		}
+	}
+	if t.bell {
+		if _, err := io.WriteString(t.w, "\a"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
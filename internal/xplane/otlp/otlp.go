@@ -0,0 +1,162 @@
+// Package otlp models a crossplane trace provisioning as OpenTelemetry spans,
+// with each resource being a span going from its creation until it got ready.
+package otlp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/brunoluiz/xpdig/internal/xplane"
+	xpv1 "github.com/crossplane/crossplane-runtime/apis/common/v1"
+	pkgv1 "github.com/crossplane/crossplane/apis/pkg/v1"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const instrumentation = "github.com/brunoluiz/xpdig"
+
+// ErrMultipleDestinations is returned when both an endpoint and a file are set.
+var ErrMultipleDestinations = errors.New("spans can be exported either to an otlp endpoint or to a file, not both")
+
+// NewExporter returns an OTLP/HTTP exporter if an endpoint is set, otherwise
+// it falls back to writing spans as JSON into a file, for offline usage.
+func NewExporter(ctx context.Context, endpoint, file string) (sdktrace.SpanExporter, error) {
+	if endpoint != "" && file != "" {
+		return nil, ErrMultipleDestinations
+	}
+	if endpoint != "" {
+		exp, err := otlptracehttp.New(ctx, otlptracehttp.WithEndpointURL(endpoint))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+		}
+		return exp, nil
+	}
+
+	f, err := os.Create(filepath.Clean(file))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp file: %w", err)
+	}
+	exp, err := stdouttrace.New(stdouttrace.WithWriter(f))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create file exporter: %w", err)
+	}
+	return &fileExporter{Exporter: exp, f: f}, nil
+}
+
+// fileExporter closes the destination file once all spans are flushed.
+type fileExporter struct {
+	*stdouttrace.Exporter
+	f *os.File
+}
+
+func (e *fileExporter) Shutdown(ctx context.Context) error {
+	return errors.Join(e.Exporter.Shutdown(ctx), e.f.Close())
+}
+
+// Export emits one trace for the given resource (usually a claim), with child
+// spans following the resource hierarchy.
+func Export(ctx context.Context, exp sdktrace.SpanExporter, r *xplane.Resource, capturedAt time.Time) error {
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exp),
+		sdktrace.WithResource(resource.NewSchemaless(
+			semconv.ServiceName("xpdig"),
+		)),
+	)
+
+	pkg := xplane.IsPkg(r.Unstructured.GroupVersionKind().GroupKind())
+	emit(ctx, tp.Tracer(instrumentation), r, pkg, capturedAt)
+
+	if err := tp.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to flush spans: %w", err)
+	}
+	return nil
+}
+
+func emit(ctx context.Context, tracer trace.Tracer, r *xplane.Resource, pkg bool, capturedAt time.Time) {
+	start, end, ready := getBoundaries(r, pkg, capturedAt)
+
+	ctx, span := tracer.Start(ctx,
+		fmt.Sprintf("%s/%s", r.Unstructured.GetKind(), r.Unstructured.GetName()),
+		trace.WithTimestamp(start),
+		trace.WithAttributes(
+			attribute.String("crossplane.kind", r.Unstructured.GetKind()),
+			attribute.String("crossplane.group", r.Unstructured.GroupVersionKind().Group),
+			attribute.String("crossplane.name", r.Unstructured.GetName()),
+			attribute.String("crossplane.health", string(xplane.GetHealth(r, pkg))),
+			attribute.Bool("crossplane.ready", ready),
+			semconv.K8SNamespaceName(r.Unstructured.GetNamespace()),
+		),
+	)
+
+	for _, c := range r.GetConditions() {
+		span.AddEvent(string(c.Type),
+			trace.WithTimestamp(getEventTime(c, start)),
+			trace.WithAttributes(
+				attribute.String("crossplane.condition.status", string(c.Status)),
+				attribute.String("crossplane.condition.reason", string(c.Reason)),
+				attribute.String("crossplane.condition.message", c.Message),
+			),
+		)
+	}
+
+	status, ok := xplane.GetStatus(r, pkg)
+	switch {
+	case r.Error != nil:
+		span.RecordError(r.Error, trace.WithTimestamp(end))
+		span.SetStatus(codes.Error, r.Error.Error())
+	case !ok:
+		span.SetStatus(codes.Error, status)
+	default:
+		span.SetStatus(codes.Ok, "")
+	}
+
+	for _, c := range r.Children {
+		emit(ctx, tracer, c, pkg, capturedAt)
+	}
+
+	span.End(trace.WithTimestamp(end))
+}
+
+// getBoundaries returns when the resource started being provisioned and when
+// it got ready. Resources that are not ready yet are considered in flight
+// until the moment the trace was captured.
+func getBoundaries(r *xplane.Resource, pkg bool, capturedAt time.Time) (time.Time, time.Time, bool) {
+	start := r.Unstructured.GetCreationTimestamp().Time
+	if start.IsZero() {
+		start = capturedAt
+	}
+
+	readyType := xpv1.TypeReady
+	if pkg {
+		readyType = pkgv1.TypeHealthy
+	}
+
+	cond := r.GetCondition(readyType)
+	if cond.Status != corev1.ConditionTrue || cond.LastTransitionTime.IsZero() {
+		return start, capturedAt, false
+	}
+
+	end := cond.LastTransitionTime.Time
+	if end.Before(start) {
+		end = start
+	}
+	return start, end, true
+}
+
+func getEventTime(c xpv1.Condition, fallback time.Time) time.Time {
+	if c.LastTransitionTime.IsZero() {
+		return fallback
+	}
+	return c.LastTransitionTime.Time
+}
@@ -0,0 +1,91 @@
+package otlp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/brunoluiz/xpdig/internal/xplane"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// keepExporter avoids the in memory exporter dropping spans on shutdown.
+type keepExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (e keepExporter) Shutdown(context.Context) error { return nil }
+
+func TestExport(t *testing.T) {
+	f, err := os.Open("../../../fixture/crossplane-resource.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	trace, err := xplane.Parse(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := tracetest.NewInMemoryExporter()
+	capturedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := Export(context.Background(), keepExporter{exp}, trace, capturedAt); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exp.GetSpans()
+	if len(spans) != 8 {
+		t.Fatalf("Export(...): want 8 spans, got %d", len(spans))
+	}
+
+	byName := map[string]tracetest.SpanStub{}
+	for _, s := range spans {
+		byName[s.Name] = s
+		if s.SpanContext.TraceID() != spans[0].SpanContext.TraceID() {
+			t.Errorf("Export(...): span %s is not part of a single trace", s.Name)
+		}
+	}
+
+	root := byName["ObjectStorage/test-resource"]
+	if root.Parent.IsValid() {
+		t.Errorf("Export(...): root span should not have a parent")
+	}
+	if root.Status.Code != codes.Ok {
+		t.Errorf("Export(...): root span should be ok, got %v", root.Status.Code)
+	}
+
+	xr := byName["XObjectStorage/test-resource-hash"]
+	if xr.Parent.SpanID() != root.SpanContext.SpanID() {
+		t.Errorf("Export(...): composite span should be a child of the claim span")
+	}
+
+	leaf := byName["User/test-resource-leaf-mid-bucket-hash"]
+	if leaf.Status.Code != codes.Error {
+		t.Errorf("Export(...): unhealthy resource span should be an error, got %v", leaf.Status.Code)
+	}
+	if len(leaf.Events) == 0 {
+		t.Errorf("Export(...): conditions should be recorded as span events")
+	}
+}
+
+func TestNewExporter(t *testing.T) {
+	if _, err := NewExporter(context.Background(), "http://localhost:4318/v1/traces", "spans.json"); !errors.Is(err, ErrMultipleDestinations) {
+		t.Errorf("NewExporter(...): want ErrMultipleDestinations, got %v", err)
+	}
+
+	exp, err := NewExporter(context.Background(), "", filepath.Join(t.TempDir(), "spans.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if err := exp.(*fileExporter).f.Close(); !errors.Is(err, os.ErrClosed) {
+		t.Errorf("Shutdown(...): want the file to be closed, got %v", err)
+	}
+}
@@ -0,0 +1,107 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenColon
+	tokenEqual
+	tokenNotEqual
+	tokenMatch
+	tokenLParen
+	tokenRParen
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind  tokenKind
+	value string
+	pos   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenString:
+		return `"` + t.value + `"`
+	default:
+		return "'" + t.value + "'"
+	}
+}
+
+func isWordRune(r rune) bool {
+	return !unicode.IsSpace(r) && !strings.ContainsRune(`():=!~"`, r)
+}
+
+func lex(q string) ([]token, error) {
+	tokens := []token{}
+	runes := []rune(q)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: tokenLParen, value: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: tokenRParen, value: ")", pos: i})
+			i++
+		case r == ':':
+			tokens = append(tokens, token{kind: tokenColon, value: ":", pos: i})
+			i++
+		case r == '=':
+			tokens = append(tokens, token{kind: tokenEqual, value: "=", pos: i})
+			i++
+		case r == '~':
+			tokens = append(tokens, token{kind: tokenMatch, value: "~", pos: i})
+			i++
+		case r == '!' && i+1 < len(runes) && runes[i+1] == '=':
+			tokens = append(tokens, token{kind: tokenNotEqual, value: "!=", pos: i})
+			i += 2
+		case r == '!':
+			tokens = append(tokens, token{kind: tokenNot, value: "!", pos: i})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, &ErrParse{Pos: i, Msg: "unterminated string"}
+			}
+			tokens = append(tokens, token{kind: tokenString, value: string(runes[i+1 : end]), pos: i})
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && isWordRune(runes[end]) {
+				end++
+			}
+			word := string(runes[i:end])
+			kind := tokenWord
+			switch strings.ToUpper(word) {
+			case "AND":
+				kind = tokenAnd
+			case "OR":
+				kind = tokenOr
+			case "NOT":
+				kind = tokenNot
+			}
+			tokens = append(tokens, token{kind: kind, value: word, pos: i})
+			i = end
+		}
+	}
+
+	return append(tokens, token{kind: tokenEOF, pos: len(runes)}), nil
+}
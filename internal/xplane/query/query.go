@@ -0,0 +1,234 @@
+// Package query implements a small query language to search crossplane
+// resources, such as `kind:Bucket AND ready=False` or `label:team=payments`.
+//
+// Predicates can be combined with AND, OR, NOT and parentheses, with
+// adjacent predicates being implicitly combined with AND. Bare words
+// match resource identifiers as a case-insensitive substring.
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// ErrParse query syntax error, with the position (in runes) where it happened.
+type ErrParse struct {
+	Pos int
+	Msg string
+}
+
+func (e *ErrParse) Error() string {
+	return fmt.Sprintf("col %d: %s", e.Pos+1, e.Msg)
+}
+
+// Expr a parsed query, which can be matched against subjects.
+type Expr interface {
+	Match(s Subject) bool
+}
+
+type andExpr struct{ l, r Expr }
+
+func (e andExpr) Match(s Subject) bool { return e.l.Match(s) && e.r.Match(s) }
+
+type orExpr struct{ l, r Expr }
+
+func (e orExpr) Match(s Subject) bool { return e.l.Match(s) || e.r.Match(s) }
+
+type notExpr struct{ e Expr }
+
+func (e notExpr) Match(s Subject) bool { return !e.e.Match(s) }
+
+type matchFn func(s Subject) bool
+
+func (f matchFn) Match(s Subject) bool { return f(s) }
+
+// Parse compiles a query. Empty queries match everything.
+func Parse(q string) (Expr, error) {
+	tokens, err := lex(q)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return matchFn(func(Subject) bool { return true }), nil
+	}
+
+	e, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, &ErrParse{Pos: t.pos, Msg: fmt.Sprintf("unexpected %s", t)}
+	}
+	return e, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token { return p.tokens[p.pos] }
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	l, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		r, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		l = orExpr{l, r}
+	}
+	return l, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	l, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenWord, tokenString, tokenNot, tokenLParen:
+			// implicit AND between adjacent predicates
+		default:
+			return l, nil
+		}
+		r, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		l = andExpr{l, r}
+	}
+}
+
+func (p *parser) parseNot() (Expr, error) {
+	if p.peek().kind == tokenNot {
+		p.next()
+		e, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{e}, nil
+	}
+	return p.parsePrimary()
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	t := p.next()
+	switch t.kind {
+	case tokenLParen:
+		e, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if r := p.next(); r.kind != tokenRParen {
+			return nil, &ErrParse{Pos: r.pos, Msg: fmt.Sprintf("expected ')' but got %s", r)}
+		}
+		return e, nil
+	case tokenString:
+		return textPredicate(t.value), nil
+	case tokenWord:
+		return p.parsePredicate(t)
+	default:
+		return nil, &ErrParse{Pos: t.pos, Msg: fmt.Sprintf("expected a predicate but got %s", t)}
+	}
+}
+
+func (p *parser) parsePredicate(field token) (Expr, error) {
+	op := p.peek()
+	switch op.kind {
+	case tokenColon, tokenEqual, tokenNotEqual, tokenMatch:
+		p.next()
+	default:
+		return keywordPredicate(field.value), nil
+	}
+
+	value := p.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, &ErrParse{Pos: value.pos, Msg: fmt.Sprintf("expected a value after '%s%s' but got %s", field.value, op.value, value)}
+	}
+
+	name := strings.ToLower(field.value)
+	if name == "label" && op.kind == tokenColon {
+		return p.parseLabel(value)
+	}
+
+	get, ok := fields[name]
+	if !ok {
+		return nil, &ErrParse{Pos: field.pos, Msg: fmt.Sprintf("unknown field '%s'", field.value)}
+	}
+	if op.kind == tokenColon && exactFields[name] {
+		op.kind = tokenEqual
+	}
+	return compare(get, op, value)
+}
+
+// parseLabel parses `label:key`, `label:key=value` and `label:key!=value`.
+func (p *parser) parseLabel(k token) (Expr, error) {
+	op := p.peek()
+	if op.kind != tokenEqual && op.kind != tokenNotEqual {
+		return matchFn(func(s Subject) bool {
+			_, ok := s.Labels[k.value]
+			return ok
+		}), nil
+	}
+	p.next()
+
+	v := p.next()
+	if v.kind != tokenWord && v.kind != tokenString {
+		return nil, &ErrParse{Pos: v.pos, Msg: fmt.Sprintf("expected a label value but got %s", v)}
+	}
+	return matchFn(func(s Subject) bool {
+		lv, ok := s.Labels[k.value]
+		return ok && (lv == v.value) == (op.kind == tokenEqual)
+	}), nil
+}
+
+func compare(get func(Subject) string, op, value token) (Expr, error) {
+	switch op.kind {
+	case tokenColon:
+		return matchFn(func(s Subject) bool {
+			return strings.Contains(strings.ToLower(get(s)), strings.ToLower(value.value))
+		}), nil
+	case tokenEqual:
+		return matchFn(func(s Subject) bool { return strings.EqualFold(get(s), value.value) }), nil
+	case tokenNotEqual:
+		return matchFn(func(s Subject) bool { return !strings.EqualFold(get(s), value.value) }), nil
+	default:
+		re, err := regexp.Compile("(?i)" + value.value)
+		if err != nil {
+			return nil, &ErrParse{Pos: value.pos, Msg: fmt.Sprintf("invalid regular expression: %s", err)}
+		}
+		return matchFn(func(s Subject) bool { return re.MatchString(get(s)) }), nil
+	}
+}
+
+func textPredicate(text string) Expr {
+	return matchFn(func(s Subject) bool {
+		return strings.Contains(strings.ToLower(s.ID), strings.ToLower(text))
+	})
+}
+
+func keywordPredicate(word string) Expr {
+	if fn, ok := keywords[strings.ToLower(word)]; ok {
+		return matchFn(fn)
+	}
+	return textPredicate(word)
+}
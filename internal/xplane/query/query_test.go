@@ -0,0 +1,157 @@
+package query
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMatch(t *testing.T) {
+	bucket := Subject{
+		ID:     "Bucket.s3.aws.upbound.io/payments-bucket",
+		Kind:   "Bucket",
+		Group:  "s3.aws.upbound.io",
+		Name:   "payments-bucket",
+		Labels: map[string]string{"team": "payments"},
+		Ready:  "False",
+		Synced: "True",
+		Status: "ReconcileError: AccessDenied: not authorized",
+	}
+	paused := Subject{
+		ID:     "XBucket.example.org/paused",
+		Kind:   "XBucket",
+		Group:  "example.org",
+		Name:   "paused",
+		Ready:  "True",
+		Synced: "True",
+		Ok:     true,
+		Paused: true,
+	}
+
+	type args struct {
+		query string
+	}
+
+	type want struct {
+		matches []bool
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"Empty": {
+			reason: "Should match everything on empty queries",
+			args:   args{query: ""},
+			want:   want{matches: []bool{true, true}},
+		},
+		"BareWord": {
+			reason: "Should match bare words as substrings of IDs",
+			args:   args{query: "PAYMENTS"},
+			want:   want{matches: []bool{true, false}},
+		},
+		"KindExact": {
+			reason: "Should match kinds exactly, regardless of case",
+			args:   args{query: "kind:bucket"},
+			want:   want{matches: []bool{true, false}},
+		},
+		"GroupSubstring": {
+			reason: "Should match groups as substrings",
+			args:   args{query: "group:s3.aws"},
+			want:   want{matches: []bool{true, false}},
+		},
+		"ConditionNotEqual": {
+			reason: "Should support negated comparisons",
+			args:   args{query: "ready!=True"},
+			want:   want{matches: []bool{true, false}},
+		},
+		"StatusRegex": {
+			reason: "Should match status against regular expressions",
+			args:   args{query: `status~"access ?denied"`},
+			want:   want{matches: []bool{true, false}},
+		},
+		"Label": {
+			reason: "Should match label key and values",
+			args:   args{query: "label:team=payments"},
+			want:   want{matches: []bool{true, false}},
+		},
+		"LabelExists": {
+			reason: "Should match label presence",
+			args:   args{query: "NOT label:team"},
+			want:   want{matches: []bool{false, true}},
+		},
+		"Keyword": {
+			reason: "Should support keywords",
+			args:   args{query: "paused"},
+			want:   want{matches: []bool{false, true}},
+		},
+		"Boolean": {
+			reason: "Should respect precedence of AND over OR, and parentheses",
+			args:   args{query: "kind:XBucket OR (synced=True ready=False) AND NOT paused"},
+			want:   want{matches: []bool{true, true}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			expr, err := Parse(tc.args.query)
+			if err != nil {
+				t.Fatalf("\n%s\nParse(...): unexpected error: %v", tc.reason, err)
+			}
+
+			for i, s := range []Subject{bucket, paused} {
+				if got := expr.Match(s); got != tc.want.matches[i] {
+					t.Errorf("\n%s\nMatch(%s): want %t, got %t", tc.reason, s.ID, tc.want.matches[i], got)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	type args struct {
+		query string
+	}
+
+	type want struct {
+		errPos int
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"ErrUnknownField": {
+			reason: "Should fail on unknown fields",
+			args:   args{query: "kind:Bucket colour=red"},
+			want:   want{errPos: 12},
+		},
+		"ErrMissingValue": {
+			reason: "Should fail on missing values",
+			args:   args{query: "ready="},
+			want:   want{errPos: 6},
+		},
+		"ErrParenthesis": {
+			reason: "Should fail on unbalanced parentheses",
+			args:   args{query: "(paused"},
+			want:   want{errPos: 7},
+		},
+		"ErrRegex": {
+			reason: "Should fail on invalid regular expressions",
+			args:   args{query: "status~["},
+			want:   want{errPos: 7},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := Parse(tc.args.query)
+			var perr *ErrParse
+			if !errors.As(err, &perr) {
+				t.Fatalf("\n%s\nParse(...): want parse error, got %v", tc.reason, err)
+			}
+			if perr.Pos != tc.want.errPos {
+				t.Errorf("\n%s\nParse(...): want error at %d, got %d (%s)", tc.reason, tc.want.errPos, perr.Pos, perr)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package query
+
+import (
+	"github.com/brunoluiz/xpdig/internal/xplane"
+)
+
+// Subject is what queries are matched against.
+type Subject struct {
+	ID        string
+	Kind      string
+	Group     string
+	Name      string
+	Namespace string
+	Labels    map[string]string
+	Ready     string
+	Synced    string
+	Status    string
+	Ok        bool
+	Paused    bool
+	Deleting  bool
+	Error     bool
+}
+
+// NewSubject extracts what is needed to query a resource. Packages have
+// their Healthy and Installed conditions mapped as Ready and Synced.
+func NewSubject(id string, r *xplane.Resource) Subject {
+	pkg := xplane.IsPkg(r.Unstructured.GroupVersionKind().GroupKind())
+	s := Subject{
+		ID:        id,
+		Kind:      r.Unstructured.GetKind(),
+		Group:     r.Unstructured.GroupVersionKind().Group,
+		Name:      r.Unstructured.GetName(),
+		Namespace: r.Unstructured.GetNamespace(),
+		Labels:    r.Unstructured.GetLabels(),
+		Paused:    r.IsPaused(),
+		Deleting:  r.Unstructured.GetDeletionTimestamp() != nil,
+		Error:     r.Error != nil,
+	}
+
+	if pkg {
+		st := xplane.GetPkgResourceStatus(r, "")
+		s.Ready, s.Synced, s.Status, s.Ok = st.Healthy, st.Installed, st.Status, st.Ok
+	} else {
+		st := xplane.GetResourceStatus(r, "")
+		s.Ready, s.Synced, s.Status, s.Ok = st.Ready, st.Synced, st.Status, st.Ok
+	}
+	return s
+}
+
+var fields = map[string]func(Subject) string{
+	"id":        func(s Subject) string { return s.ID },
+	"kind":      func(s Subject) string { return s.Kind },
+	"group":     func(s Subject) string { return s.Group },
+	"name":      func(s Subject) string { return s.Name },
+	"ns":        func(s Subject) string { return s.Namespace },
+	"namespace": func(s Subject) string { return s.Namespace },
+	"ready":     func(s Subject) string { return s.Ready },
+	"healthy":   func(s Subject) string { return s.Ready },
+	"synced":    func(s Subject) string { return s.Synced },
+	"installed": func(s Subject) string { return s.Synced },
+	"status":    func(s Subject) string { return s.Status },
+}
+
+// exactFields are compared by equality, instead of substring, when using `field:value`.
+var exactFields = map[string]bool{
+	"kind": true, "ns": true, "namespace": true,
+	"ready": true, "healthy": true, "synced": true, "installed": true,
+}
+
+var keywords = map[string]func(Subject) bool{
+	"paused":    func(s Subject) bool { return s.Paused },
+	"deleting":  func(s Subject) bool { return s.Deleting },
+	"unhealthy": func(s Subject) bool { return !s.Ok || s.Error },
+	"error":     func(s Subject) bool { return s.Error },
+}
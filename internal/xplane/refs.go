@@ -0,0 +1,83 @@
+package xplane
+
+import (
+	"strconv"
+
+	"github.com/crossplane/crossplane-runtime/pkg/fieldpath"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const groupAPIExtensions = "apiextensions.crossplane.io"
+
+// Reference is an object referenced by a resource which is not part of its
+// trace (eg: its Composition or connection Secret).
+type Reference struct {
+	Field     string // where it is referenced, eg: spec.compositionRef
+	Kind      string
+	Group     string // empty for core objects or if it can not be known
+	Name      string
+	Namespace string // empty for cluster scoped objects
+}
+
+// Type returns the kind and group of the object, eg: `Composition.apiextensions.crossplane.io`.
+func (r Reference) Type() string {
+	if r.Group == "" {
+		return r.Kind
+	}
+	return r.Kind + "." + r.Group
+}
+
+// ID returns the object in the same format as the trace rows.
+func (r Reference) ID() string {
+	return r.Type() + "/" + r.Name
+}
+
+// objectRef is how most references are set in the spec.
+type objectRef struct {
+	APIVersion string `json:"apiVersion"`
+	Kind       string `json:"kind"`
+	Name       string `json:"name"`
+	Namespace  string `json:"namespace"`
+}
+
+// GetReferences returns the objects referenced by the resource, checking both
+// `spec` and `spec.crossplane` (Crossplane v2). ProviderConfigs have no group,
+// as it depends on the provider, which leaves it for kubectl to find out.
+func GetReferences(r *Resource) []Reference {
+	p := fieldpath.Pave(r.Unstructured.Object)
+	refs := []Reference{}
+
+	single := func(field, kind, group string, namespaced bool) {
+		ref := objectRef{}
+		if err := p.GetValueInto(field, &ref); err != nil || ref.Name == "" {
+			return
+		}
+		if ref.Kind != "" {
+			kind = ref.Kind
+		}
+		if ref.APIVersion != "" {
+			group = schema.FromAPIVersionAndKind(ref.APIVersion, kind).Group
+		}
+		if namespaced && ref.Namespace == "" {
+			ref.Namespace = r.Unstructured.GetNamespace()
+		}
+		refs = append(refs, Reference{Field: field, Kind: kind, Group: group, Name: ref.Name, Namespace: ref.Namespace})
+	}
+
+	for _, spec := range []string{"spec", "spec.crossplane"} {
+		single(spec+".providerConfigRef", "ProviderConfig", "", false)
+		single(spec+".compositionRef", "Composition", groupAPIExtensions, false)
+		single(spec+".compositionRevisionRef", "CompositionRevision", groupAPIExtensions, false)
+		single(spec+".writeConnectionSecretToRef", "Secret", "", true)
+		single(spec+".runtimeConfigRef", "DeploymentRuntimeConfig", "pkg.crossplane.io", false)
+
+		envs := []objectRef{}
+		if err := p.GetValueInto(spec+".environmentConfigRefs", &envs); err != nil {
+			continue
+		}
+		for i := range envs {
+			single(spec+".environmentConfigRefs["+strconv.Itoa(i)+"]", "EnvironmentConfig", groupAPIExtensions, false)
+		}
+	}
+	return refs
+}
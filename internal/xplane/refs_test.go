@@ -0,0 +1,82 @@
+package xplane
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestGetReferences(t *testing.T) {
+	type args struct {
+		object map[string]any
+	}
+
+	type want struct {
+		refs []Reference
+	}
+	tests := map[string]struct {
+		reason string
+		args   args
+		want   want
+	}{
+		"NoReferences": {
+			reason: "Should return no references if the spec has none",
+			args:   args{object: map[string]any{"spec": map[string]any{"forProvider": map[string]any{}}}},
+			want:   want{refs: []Reference{}},
+		},
+		"Claim": {
+			reason: "Should default the connection Secret namespace to the one of the resource",
+			args: args{object: map[string]any{
+				"metadata": map[string]any{"name": "claim", "namespace": "team"},
+				"spec": map[string]any{
+					"compositionRef":             map[string]any{"name": "xbuckets"},
+					"compositionRevisionRef":     map[string]any{"name": "xbuckets-abc"},
+					"writeConnectionSecretToRef": map[string]any{"name": "conn"},
+				},
+			}},
+			want: want{refs: []Reference{
+				{Field: "spec.compositionRef", Kind: "Composition", Group: groupAPIExtensions, Name: "xbuckets"},
+				{Field: "spec.compositionRevisionRef", Kind: "CompositionRevision", Group: groupAPIExtensions, Name: "xbuckets-abc"},
+				{Field: "spec.writeConnectionSecretToRef", Kind: "Secret", Name: "conn", Namespace: "team"},
+			}},
+		},
+		"EnvironmentConfigs": {
+			reason: "Should return every EnvironmentConfig, with the group taken from the API version",
+			args: args{object: map[string]any{
+				"spec": map[string]any{
+					"environmentConfigRefs": []any{
+						map[string]any{"apiVersion": "apiextensions.crossplane.io/v1beta1", "kind": "EnvironmentConfig", "name": "a"},
+						map[string]any{"name": "b"},
+					},
+				},
+			}},
+			want: want{refs: []Reference{
+				{Field: "spec.environmentConfigRefs[0]", Kind: "EnvironmentConfig", Group: groupAPIExtensions, Name: "a"},
+				{Field: "spec.environmentConfigRefs[1]", Kind: "EnvironmentConfig", Group: groupAPIExtensions, Name: "b"},
+			}},
+		},
+		"CrossplaneV2": {
+			reason: "Should look into spec.crossplane and take the ProviderConfig kind from the reference",
+			args: args{object: map[string]any{
+				"spec": map[string]any{
+					"providerConfigRef": map[string]any{"kind": "ClusterProviderConfig", "name": "default"},
+					"crossplane":        map[string]any{"compositionRef": map[string]any{"name": "xnets"}},
+				},
+			}},
+			want: want{refs: []Reference{
+				{Field: "spec.providerConfigRef", Kind: "ClusterProviderConfig", Name: "default"},
+				{Field: "spec.crossplane.compositionRef", Kind: "Composition", Group: groupAPIExtensions, Name: "xnets"},
+			}},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := GetReferences(&Resource{Unstructured: unstructured.Unstructured{Object: tc.args.object}})
+			if diff := cmp.Diff(tc.want.refs, got); diff != "" {
+				t.Errorf("\n%s\nGetReferences(): -want, +got:\n%s", tc.reason, diff)
+			}
+		})
+	}
+}